@@ -0,0 +1,50 @@
+package errgroup
+
+import "sync"
+
+// Barrier is a reusable rendezvous point for the functions passed to a
+// Group. Each time the required number of functions have reached it, they
+// are all released together and the Barrier resets for the next phase.
+type Barrier struct {
+	group *Group
+	n     int
+
+	lock    sync.Mutex
+	arrived int
+	release chan struct{}
+}
+
+// Barrier returns a new Barrier that releases the functions waiting on it
+// once n of them have reached it.
+func (g *Group) Barrier(n int) *Barrier {
+	return &Barrier{
+		group:   g,
+		n:       n,
+		release: make(chan struct{}),
+	}
+}
+
+// Wait blocks until n functions, including the caller, have called Wait,
+// and then returns nil. If the Group is cancelled while the caller is
+// blocked, Wait instead returns a CancelError.
+func (b *Barrier) Wait() error {
+	b.lock.Lock()
+	b.arrived++
+	if b.arrived >= b.n {
+		close(b.release)
+		b.release = make(chan struct{})
+		b.arrived = 0
+		b.lock.Unlock()
+		return nil
+	}
+
+	release := b.release
+	b.lock.Unlock()
+
+	select {
+	case <-release:
+		return nil
+	case <-b.group.done():
+		return b.group.cancelError()
+	}
+}
@@ -0,0 +1,122 @@
+package errgroup_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jordanhasgul/errgroup"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBarrier_Wait(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			numGoroutines = 1 << 4
+			numPhases     = 1 << 2
+		)
+
+		var (
+			eg      errgroup.Group
+			barrier = eg.Barrier(numGoroutines)
+			reached [numPhases]atomic.Int32
+		)
+		for range numGoroutines {
+			err := eg.Go(func() error {
+				for phase := range numPhases {
+					reached[phase].Add(1)
+
+					err := barrier.Wait()
+					if err != nil {
+						return err
+					}
+
+					n := reached[phase].Load()
+					if n != numGoroutines {
+						return fmt.Errorf("released early - got: %d, want: %d", n, numGoroutines)
+					}
+				}
+
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.Wait()
+		require.NoError(t, err)
+	})
+
+	t.Run("with cancel", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var (
+			errTask = errors.New("task error")
+
+			ctx  = context.Background()
+			_, c = errgroup.WithCancel(ctx)
+			eg   = errgroup.New(c)
+
+			barrier   = eg.Barrier(numGoroutines + 1)
+			cancelled atomic.Int32
+		)
+		for range numGoroutines {
+			err := eg.Go(func() error {
+				err := barrier.Wait()
+
+				var ce *errgroup.CancelError
+				if errors.As(err, &ce) && errors.Is(err, errTask) {
+					cancelled.Add(1)
+				}
+
+				return err
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.Go(func() error {
+			return errTask
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.ErrorIs(t, err, errTask)
+		require.Equal(t, int32(numGoroutines), cancelled.Load())
+	})
+
+	t.Run("with group cancel", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var (
+			eg        errgroup.Group
+			barrier   = eg.Barrier(numGoroutines + 1)
+			cancelled atomic.Int32
+		)
+		for range numGoroutines {
+			err := eg.Go(func() error {
+				err := barrier.Wait()
+
+				var ce *errgroup.CancelError
+				if errors.As(err, &ce) {
+					cancelled.Add(1)
+				}
+
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		eg.Cancel()
+
+		err := eg.Wait()
+		require.NoError(t, err)
+		require.Equal(t, int32(numGoroutines), cancelled.Load())
+	})
+}
@@ -3,24 +3,130 @@
 package errgroup
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"runtime"
+	"runtime/debug"
+	"slices"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/jordanhasgul/multierr"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Group manages the execution of goroutines that run functions of type
 // func() error.
 type Group struct {
-	semaphore chan struct{}
-	wg        sync.WaitGroup
-	cancelled atomic.Bool
-	cancel    context.CancelFunc
+	configurers []Configurer
 
-	errLock sync.Mutex
-	err     error
+	semaphore    atomic.Pointer[semaphore]
+	urgent       *semaphore
+	spin         int
+	wg           sync.WaitGroup
+	cancelled    atomic.Bool
+	waited       atomic.Bool
+	sealed       atomic.Bool
+	aborted      atomic.Bool
+	pauseLock    sync.Mutex
+	pauseCh      chan struct{}
+	parent       context.Context
+	ctx          context.Context
+	cancel       context.CancelCauseFunc
+	cancelOnce   sync.Once
+	cancelLock   sync.Mutex
+	cancelCh     chan struct{}
+	waitLock     sync.Mutex
+	closers      []io.Closer
+	closeOnce    sync.Once
+	linkLock     sync.Mutex
+	linked       []*Group
+	recover      bool
+	firstOnly    bool
+	firstSuccess bool
+	ignored      []error
+	weighted     *semaphore
+	retries      uint
+	backoff      *backoffConfigurer
+	strict       bool
+	onError      func(error)
+	joiner       func(existing, new error) error
+	observer     *taskObserverConfigurer
+	maxErrors    uint
+	logger       *slog.Logger
+	ordered      bool
+	pool         *pool
+	jitter       *jitterer
+	cancelOn     func(error) bool
+	tracer       *tracerConfigurer
+	adaptive     *adaptiveLimiter
+	deadlock     time.Duration
+	succeeded    atomic.Bool
+	transform    func(error) error
+	mws          []func(next func() error) func() error
+	fault        *faultInjector
+	errWriter    *errorWriter
+	heartbeat    *heartbeat
+	warmup       func() error
+	warmupErr    error
+	hookPanic    func(any)
+	onRelease    func()
+
+	debounce      time.Duration
+	debounceLock  sync.Mutex
+	debounceTimer *time.Timer
+
+	escalation  *timeoutEscalation
+	taskTimeout time.Duration
+	maxLifetime time.Duration
+	lifetime    *time.Timer
+
+	serial     bool
+	serialLock sync.Mutex
+	serialTail chan struct{}
+
+	finalLock sync.Mutex
+	finals    []func() error
+
+	doneLock   sync.Mutex
+	doneCh     chan struct{}
+	doneClosed bool
+
+	waitCtxLock sync.Mutex
+	waitCtx     context.Context
+	waitCancel  context.CancelFunc
+	waitCtxDone bool
+
+	onceLock sync.Mutex
+	onceKeys map[string]struct{}
+
+	blocked   atomic.Int64
+	active    atomic.Int64
+	peak      atomic.Int64
+	completed atomic.Int64
+	failed    atomic.Int64
+	skipped   atomic.Int64
+	launched  atomic.Int64
+	startOnce sync.Once
+	start     time.Time
+
+	errLock     sync.Mutex
+	err         error
+	errCount    atomic.Int64
+	orderedErrs []orderedError
+	trigger     *CancelError
+	firstErr    error
+	firstErrCh  chan error
 }
 
 // Configurer is implemented by any type that has a configure method. The
@@ -33,153 +139,3188 @@ type Configurer interface {
 // configurers.
 func New(configurers ...Configurer) *Group {
 	group := &Group{}
+	group.init(configurers...)
+	return group
+}
+
+// WithContext returns a new Group and a context.Context derived from ctx,
+// mirroring the errgroup.WithContext function of golang.org/x/sync. It is
+// shorthand for passing the Configurer returned by WithCancel to New, so the
+// derived context.Context is cancelled in the same circumstances.
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, configurer := WithCancel(ctx)
+	return New(configurer), ctx
+}
+
+func (g *Group) init(configurers ...Configurer) {
+	g.configurers = configurers
 	for _, configurer := range configurers {
-		configurer.configure(group)
+		configurer.configure(g)
 	}
 
-	return group
+	g.runWarmup()
+}
+
+// runWarmup calls the function supplied to WithWarmup, if any, cancelling
+// the Group with its error if it fails.
+func (g *Group) runWarmup() {
+	g.warmupErr = nil
+	if g.warmup == nil {
+		return
+	}
+
+	err := g.warmup()
+	if err != nil {
+		g.warmupErr = err
+		g.record(-1, err)
+		g.cancelled.Store(true)
+		g.doCancel(err)
+	}
+}
+
+// Clone returns a new Group that has been configured by applying the same
+// configurers that were supplied to New when constructing g. The clone
+// shares no goroutines, errors or cancellation state with g, though any
+// values held by the configurers, such as the io.Closer supplied to
+// WithCloseOnCancel, are shared. If g was configured using WithCancel, the
+// clone derives its own context.Context from the one supplied to
+// WithCancel. If g was configured using WithWarmup, the warmup function is
+// called again for the clone.
+func (g *Group) Clone() *Group {
+	clone := &Group{configurers: g.configurers}
+	for _, configurer := range g.configurers {
+		configurer.configure(clone)
+	}
+
+	if clone.parent != nil {
+		clone.setCancel(context.WithCancelCause(clone.parent))
+	}
+
+	clone.runWarmup()
+	return clone
 }
 
 // LimitError indicates that a Group has reached its limit.
 type LimitError struct {
 	limit int
+	inUse int
+
+	// queued is the number of callers that were already waiting for the
+	// limit. Waiters are served first, so TryGo fails while any are queued,
+	// even if fewer than limit goroutines are in use.
+	queued int
+
+	// weighted is set when the limit is the weighted limit of the Group, in
+	// which case available is the number of units that were available.
+	weighted  bool
+	available int64
 }
 
 var _ error = (*LimitError)(nil)
 
 func (e LimitError) Error() string {
-	errorString := "group has reached the limit of %d goroutines"
-	return fmt.Sprintf(errorString, e.limit)
+	var errorString string
+	if e.weighted {
+		errorString = "group has only %d of its weighted limit of %d available"
+		errorString = fmt.Sprintf(errorString, e.available, e.limit)
+	} else {
+		errorString = "group is at %d/%d goroutines"
+		errorString = fmt.Sprintf(errorString, e.inUse, e.limit)
+	}
+
+	if e.queued > 0 {
+		errorString = fmt.Sprintf("%s, with %d already waiting", errorString, e.queued)
+	}
+
+	return errorString
 }
 
-// CancelError indicates that a Group has been cancelled.
-type CancelError struct{}
+// WeightError indicates that a function passed to Group.GoWeighted has a
+// weight that exceeds the weighted limit of a Group, so could never run.
+type WeightError struct {
+	weight int64
+	limit  int64
+}
+
+var _ error = (*WeightError)(nil)
+
+func (e WeightError) Error() string {
+	errorString := "weight of %d exceeds the weighted limit of %d"
+	return fmt.Sprintf(errorString, e.weight, e.limit)
+}
+
+// CancelError indicates that a Group has been cancelled. If the
+// cancellation was triggered by a function passed to the Group returning a
+// non-nil error, the CancelError wraps that error.
+type CancelError struct {
+	err   error
+	index int
+}
 
 var _ error = (*CancelError)(nil)
 
 func (c CancelError) Error() string {
-	return "group has been cancelled"
+	if c.err == nil {
+		return "group has been cancelled"
+	}
+
+	errorString := "group has been cancelled by task %d: %v"
+	return fmt.Sprintf(errorString, c.index, c.err)
+}
+
+// Unwrap returns the error that triggered the cancellation, or nil if the
+// cancellation was not triggered by a function returning a non-nil error.
+func (c CancelError) Unwrap() error {
+	return c.err
+}
+
+// TaskIndex returns the index of the function whose error triggered the
+// cancellation, counting from zero in the order functions were launched,
+// and reports whether the cancellation was triggered in that way.
+func (c CancelError) TaskIndex() (int, bool) {
+	return c.index, c.err != nil
+}
+
+// WaitedError indicates that a function was passed to a Group, that has
+// been configured using WithStrictLifecycle, after Group.Wait was called.
+type WaitedError struct{}
+
+var _ error = (*WaitedError)(nil)
+
+func (e WaitedError) Error() string {
+	return "group has already been waited on"
+}
+
+// SealedError indicates that a function was passed to a Group after
+// Group.Seal was called.
+type SealedError struct{}
+
+var _ error = (*SealedError)(nil)
+
+func (e SealedError) Error() string {
+	return "group has been sealed"
+}
+
+// PausedError indicates that Group.TryGo was called while the Group was
+// paused by Group.Pause.
+type PausedError struct{}
+
+var _ error = (*PausedError)(nil)
+
+func (e PausedError) Error() string {
+	return "group has been paused"
+}
+
+// DeadlockError indicates that Group.Wait gave up waiting because none of
+// the goroutines managed by a Group, that has been configured using
+// WithDeadlockTimeout, finished executing for the configured duration.
+type DeadlockError struct {
+	timeout time.Duration
+	active  int
+}
+
+var _ error = (*DeadlockError)(nil)
+
+func (e DeadlockError) Error() string {
+	errorString := "group made no progress for %v with %d goroutines still active"
+	return fmt.Sprintf(errorString, e.timeout, e.active)
+}
+
+// StoppedError indicates that Group.GoWithStop gave up waiting for the
+// number of goroutines managed by a Group to fall below its limit.
+type StoppedError struct{}
+
+var _ error = (*StoppedError)(nil)
+
+func (e StoppedError) Error() string {
+	return "group stopped waiting to launch goroutine"
+}
+
+// PanicError indicates that a function passed to a Group configured using
+// WithRecover panicked.
+type PanicError struct {
+	// Value is the value that was recovered from the panic.
+	Value any
+
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack []byte
+}
+
+var _ error = (*PanicError)(nil)
+
+func (e PanicError) Error() string {
+	errorString := "goroutine panicked: %v\n\n%s"
+	return fmt.Sprintf(errorString, e.Value, e.Stack)
+}
+
+// LifetimeExceededError indicates that a Group was cancelled because it
+// exceeded the maximum lifetime configured by WithMaxLifetime.
+type LifetimeExceededError struct {
+	lifetime time.Duration
+}
+
+var _ error = (*LifetimeExceededError)(nil)
+
+func (e LifetimeExceededError) Error() string {
+	errorString := "group has exceeded its maximum lifetime of %s"
+	return fmt.Sprintf(errorString, e.lifetime)
+}
+
+// WaitInterruptedError indicates that Group.WaitContext returned because its
+// context.Context was done before all goroutines managed by a Group had
+// finished executing.
+type WaitInterruptedError struct {
+	err error
+}
+
+var _ error = (*WaitInterruptedError)(nil)
+
+func (e WaitInterruptedError) Error() string {
+	errorString := "group wait was interrupted: %s"
+	return fmt.Sprintf(errorString, e.err)
+}
+
+// Unwrap returns the error of the context.Context that interrupted the wait.
+func (e WaitInterruptedError) Unwrap() error {
+	return e.err
 }
 
 // Go launch f in another goroutine. It blocks until the new goroutine can
 // be added without causing number of goroutines managed by the Group to
-// exceed its limit. If the Group has been cancelled, a CancelError is
-// returned.
+// exceed its limit. If the Group has been cancelled, including while Go is
+// blocked, a CancelError is returned.
 func (g *Group) Go(f func() error) error {
-	if g.cancelled.Load() {
-		return &CancelError{}
+	return g.goTask(task{f: f})
+}
+
+// Submit behaves like Group.Go, except that it also returns a Future that
+// can be used to wait for f alone to finish executing. The error returned
+// by f is still aggregated by Group.Wait.
+func (g *Group) Submit(f func() error) (*Future, error) {
+	future := &Future{done: make(chan struct{})}
+	err := g.goTask(task{f: f, finish: future.resolve})
+	if err != nil {
+		return nil, err
+	}
+
+	return future, nil
+}
+
+// goTask implements Group.Go, launching t once the Group is able to.
+func (g *Group) goTask(t task) error {
+	err := g.check()
+	if err != nil {
+		return err
+	}
+
+	if !g.awaitResume(g.done()) {
+		g.skipped.Add(1)
+		return g.cancelError()
+	}
+
+	sem := g.semaphore.Load()
+	if sem != nil {
+		if !g.acquire(sem, g.done()) {
+			g.skipped.Add(1)
+			return g.cancelError()
+		}
+	}
+
+	t.sem = sem
+	g.launch(t)
+	return nil
+}
+
+// Future represents the eventual outcome of a function passed to
+// Group.Submit.
+type Future struct {
+	done chan struct{}
+	err  error
+}
+
+// Wait blocks until the function that the Future represents has finished
+// executing and returns the error it returned. Wait may be called multiple
+// times, and from multiple goroutines.
+func (f *Future) Wait() error {
+	<-f.done
+	return f.err
+}
+
+func (f *Future) resolve(err error) {
+	f.err = err
+	close(f.done)
+}
+
+// GoWeighted behaves like Group.Go, except that before calling f in a new
+// goroutine it first blocks until weight units of the weighted limit of the
+// Group are available, holding them until f returns. If weight exceeds the
+// weighted limit, GoWeighted returns a WeightError immediately. weight must
+// not be negative.
+//
+// If the Group has not been configured using WithWeightedLimit, weight is
+// ignored.
+func (g *Group) GoWeighted(weight int64, f func() error) error {
+	sem := g.weighted
+	if sem == nil {
+		return g.Go(f)
+	}
+
+	err := g.check()
+	if err != nil {
+		return err
+	}
+
+	_, limit := sem.size()
+	if weight > limit {
+		return &WeightError{weight: weight, limit: limit}
+	}
+
+	if !g.acquireN(sem, weight, g.done()) {
+		g.skipped.Add(1)
+		return g.cancelError()
+	}
+
+	err = g.Go(func() error {
+		defer sem.release(weight)
+		return f()
+	})
+	if err != nil {
+		sem.release(weight)
+	}
+
+	return err
+}
+
+// TryGoWeighted behaves like Group.GoWeighted, except that it calls
+// Group.TryGo rather than Group.Go, and returns a LimitError reporting the
+// number of units available, rather than blocking, if weight units of the
+// weighted limit of the Group are not available right now.
+func (g *Group) TryGoWeighted(weight int64, f func() error) error {
+	sem := g.weighted
+	if sem == nil {
+		return g.TryGo(f)
+	}
+
+	err := g.check()
+	if err != nil {
+		return err
+	}
+
+	used, limit, queued, ok := sem.tryAcquireSnapshot(weight)
+	if weight > limit {
+		return &WeightError{weight: weight, limit: limit}
+	}
+
+	if !ok {
+		return &LimitError{
+			limit:     int(limit),
+			queued:    queued,
+			weighted:  true,
+			available: max(limit-used, 0),
+		}
+	}
+
+	err = g.TryGo(func() error {
+		defer sem.release(weight)
+		return f()
+	})
+	if err != nil {
+		sem.release(weight)
+	}
+
+	return err
+}
+
+// GoWithStop behaves like Group.Go, except that if it is blocked waiting for
+// the number of goroutines managed by the Group to fall below its limit, or
+// for the Group to be resumed, when stop is closed, it gives up and returns a
+// StoppedError. If the Group is cancelled while GoWithStop is blocked, it
+// returns a CancelError instead.
+func (g *Group) GoWithStop(stop <-chan struct{}, f func() error) error {
+	err := g.check()
+	if err != nil {
+		return err
+	}
+
+	merged, unlink := mergeStop(stop, g.done())
+	defer unlink()
+
+	if !g.awaitResume(merged) {
+		return g.stoppedError(stop)
+	}
+
+	sem := g.semaphore.Load()
+	if sem != nil {
+		if !g.acquire(sem, merged) {
+			return g.stoppedError(stop)
+		}
+	}
+
+	g.doGo(f, sem)
+	return nil
+}
+
+// stoppedError returns the error that Group.GoWithStop returns when it gives
+// up waiting to launch a function, which is a StoppedError if stop is
+// closed, or a CancelError otherwise.
+func (g *Group) stoppedError(stop <-chan struct{}) error {
+	select {
+	case <-stop:
+		return &StoppedError{}
+	default:
+		g.skipped.Add(1)
+		return g.cancelError()
 	}
+}
+
+// mergeStop returns a channel that is closed once either of a or b is
+// closed, alongside a function that releases the resources associated with
+// it, which must be called once the channel is no longer needed.
+func mergeStop(a, b <-chan struct{}) (<-chan struct{}, func()) {
+	var (
+		merged = make(chan struct{})
+		quit   = make(chan struct{})
+	)
+	go func() {
+		select {
+		case <-a:
+		case <-b:
+		case <-quit:
+			return
+		}
+		close(merged)
+	}()
+
+	unlink := func() {
+		close(quit)
+	}
+	return merged, unlink
+}
+
+// GoWithContext behaves like Group.Go, except that if it is blocked waiting
+// for the number of goroutines managed by the Group to fall below its limit,
+// or for the Group to be resumed, when ctx is done, it gives up and returns
+// ctx.Err(). If the Group is cancelled while GoWithContext is blocked, it
+// returns a CancelError instead. ctx only bounds how long GoWithContext
+// waits to launch f; once launched, f runs to completion regardless of ctx.
+func (g *Group) GoWithContext(ctx context.Context, f func() error) error {
+	err := g.check()
+	if err != nil {
+		return err
+	}
+
+	stop, unlink := mergeStop(ctx.Done(), g.done())
+	defer unlink()
 
-	if g.semaphore != nil {
-		g.semaphore <- struct{}{}
+	if !g.awaitResume(stop) {
+		return g.stopError(ctx)
 	}
 
-	g.doGo(f)
+	sem := g.semaphore.Load()
+	if sem != nil {
+		if !g.acquire(sem, stop) {
+			return g.stopError(ctx)
+		}
+	}
+
+	g.doGo(f, sem)
 	return nil
 }
 
+// stopError returns the error that Group.GoWithContext returns when it gives
+// up waiting to launch a function, which is ctx.Err() if ctx is done, or a
+// CancelError otherwise.
+func (g *Group) stopError(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	g.skipped.Add(1)
+	return g.cancelError()
+}
+
+// GoCtx behaves like Group.Go, except that f is passed the context.Context
+// derived by WithCancel if the Group was configured using it, or
+// context.Background otherwise. If the Group was configured using
+// WithTaskTimeout or WithTimeoutEscalation, that context.Context is further
+// limited by the timeout.
+func (g *Group) GoCtx(f func(context.Context) error) error {
+	ctxF := func(ctx context.Context) error {
+		if g.taskTimeout > 0 {
+			ctx, cancel := context.WithTimeout(ctx, g.taskTimeout)
+			defer cancel()
+			return f(ctx)
+		}
+
+		return f(ctx)
+	}
+	return g.goTask(task{ctxF: ctxF})
+}
+
 // TryGo tries to launch f in another goroutine. If it could not, TryGo
 // returns an error explaining why:
 //
 //   - A CancelError if the Group has been cancelled.
+//   - A WaitedError if the Group has been configured using
+//     WithStrictLifecycle and Group.Wait has been called.
+//   - A SealedError if Group.Seal has been called.
+//   - A PausedError if Group.Pause has been called.
 //   - A LimitError if launching f in another goroutine would cause the
 //     number of goroutines managed by the Group to exceed its limit.
 func (g *Group) TryGo(f func() error) error {
-	if g.cancelled.Load() {
-		return &CancelError{}
+	err := g.check()
+	if err != nil {
+		return err
 	}
 
-	if g.semaphore != nil {
-		select {
-		case g.semaphore <- struct{}{}:
-		default:
+	if g.paused() != nil {
+		return &PausedError{}
+	}
+
+	sem := g.semaphore.Load()
+	if sem != nil {
+		used, limit, queued, ok := sem.tryAcquireSnapshot(1)
+		if !ok {
 			return &LimitError{
-				limit: cap(g.semaphore),
+				limit:  int(limit),
+				inUse:  int(used),
+				queued: queued,
 			}
 		}
 	}
 
-	g.doGo(f)
+	g.doGo(f, sem)
 	return nil
 }
 
-func (g *Group) doGo(f func() error) {
-	g.wg.Add(1)
-	go func() {
-		defer func() {
-			g.wg.Done()
-
-			if g.semaphore != nil {
-				_ = <-g.semaphore
-			}
-		}()
-
+// GoLabeled behaves like Group.Go, except that if f returns a non-nil
+// error, the error is prefixed with label, in the form "label: err", so that
+// it can be attributed within the aggregated error. The original error can
+// still be retrieved using errors.Unwrap, errors.Is and errors.As. The
+// prefixed error is what is passed to the handler configured using
+// WithErrorHandler, and the logger configured using WithLogger also
+// records label alongside it.
+func (g *Group) GoLabeled(label string, f func() error) error {
+	return g.Go(func() error {
 		err := f()
 		if err != nil {
-			if !g.cancelled.Load() {
-				if g.cancel != nil {
-					g.cancel()
-				}
-
-				g.errLock.Lock()
-				defer g.errLock.Unlock()
-				g.err = multierr.Append(g.err, err)
-			}
+			return &labeledError{label: label, err: err}
 		}
-	}()
+
+		return nil
+	})
 }
 
-// Wait blocks until all goroutines managed by the Group have finished
-// executing and returns an error that aggregates any errors that occurred
-// within each goroutine.
-func (g *Group) Wait() error {
-	g.wg.Wait()
+type labeledError struct {
+	label string
+	err   error
+}
 
-	if g.cancel != nil {
-		g.cancel()
+var _ error = (*labeledError)(nil)
+
+func (e labeledError) Error() string {
+	errorString := "%s: %v"
+	return fmt.Sprintf(errorString, e.label, e.err)
+}
+
+func (e labeledError) Unwrap() error {
+	return e.err
+}
+
+// GoN calls Group.Go n times, passing each call of f its index, from 0 to
+// n-1. If Group.Go returns an error, GoN returns it without launching the
+// remaining calls of f.
+func (g *Group) GoN(n int, f func(i int) error) error {
+	for i := range n {
+		err := g.Go(func() error {
+			return f(i)
+		})
+		if err != nil {
+			return err
+		}
 	}
 
-	g.errLock.Lock()
-	defer g.errLock.Unlock()
-	return g.err
+	return nil
 }
 
-type cancelConfigurer struct {
-	cancel context.CancelFunc
+// TryGoN behaves like Group.GoN, except that it calls Group.TryGo, so it
+// returns a LimitError rather than blocking if launching another call of f
+// would cause the number of goroutines managed by the Group to exceed its
+// limit.
+func (g *Group) TryGoN(n int, f func(i int) error) error {
+	for i := range n {
+		err := g.TryGo(func() error {
+			return f(i)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-var _ Configurer = (*cancelConfigurer)(nil)
+// GoUrgent behaves like Group.Go, except that if the number of goroutines
+// managed by the Group has reached its limit, f may still be launched
+// immediately using the additional capacity configured by
+// WithUrgentOvershoot. Only if that is also exhausted does GoUrgent block
+// until the number of goroutines falls below the limit, returning a
+// CancelError if the Group is cancelled while it is blocked.
+//
+// Overshooting the limit means more goroutines may be active than the limit
+// was chosen to allow, so the overshoot should be kept small.
+func (g *Group) GoUrgent(f func() error) error {
+	err := g.check()
+	if err != nil {
+		return err
+	}
 
-func (c cancelConfigurer) configure(group *Group) {
-	group.cancel = func() {
-		group.cancelled.Store(true)
-		c.cancel()
+	if !g.awaitResume(g.done()) {
+		g.skipped.Add(1)
+		return g.cancelError()
 	}
+
+	sem := g.semaphore.Load()
+	switch {
+	case sem == nil:
+	case sem.tryAcquire(1):
+	case g.urgent != nil && g.urgent.tryAcquire(1):
+		sem = g.urgent
+	default:
+		if !g.acquire(sem, g.done()) {
+			g.skipped.Add(1)
+			return g.cancelError()
+		}
+	}
+
+	g.doGo(f, sem)
+	return nil
 }
 
-// WithCancel returns context.Context derived from ctx and a Configurer. The
-// returned Configurer configures a Group to cancel the derived
-// context.Context when:
+// GoAutoChunk divides items into as many chunks of roughly equal size as the
+// limit of g, or as runtime.GOMAXPROCS(0) if g has no limit, and launches f
+// on each chunk using Group.Go. It returns once every chunk has been
+// launched, or with the first error returned by Group.Go. Errors returned by
+// f are aggregated by Group.Wait.
 //
-//   - The first time a function passed to Group.Go returns a non-nil error.
-//   - The first time a call to Group.Wait returns.
-func WithCancel(ctx context.Context) (context.Context, Configurer) {
-	ctx, cancel := context.WithCancel(ctx)
-	return ctx, &cancelConfigurer{cancel}
+// If the limit of g is zero, items are launched as a single chunk, so
+// GoAutoChunk blocks until the limit is raised, as Group.Go does.
+func GoAutoChunk[T any](g *Group, items []T, f func(chunk []T) error) error {
+	numChunks := runtime.GOMAXPROCS(0)
+	if sem := g.semaphore.Load(); sem != nil {
+		_, limit := sem.size()
+		numChunks = int(limit)
+	}
+	numChunks = min(max(numChunks, 1), len(items))
+
+	for i := range numChunks {
+		var (
+			start = i * len(items) / numChunks
+			end   = (i + 1) * len(items) / numChunks
+			chunk = items[start:end]
+		)
+		err := g.Go(func() error {
+			return f(chunk)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-type limitConfigurer struct {
-	limit uint
+// Map launches f on each of items using Group.Go and waits for them to
+// finish, returning their results at the same positions as the items they
+// were derived from, alongside the error returned by Group.Wait. The result
+// for an item on which f failed, or which was not launched because g was
+// cancelled, is the zero value of R.
+func Map[T, R any](g *Group, items []T, f func(T) (R, error)) ([]R, error) {
+	var (
+		results = make([]R, len(items))
+		goErr   error
+	)
+	for i, item := range items {
+		goErr = g.Go(func() error {
+			result, err := f(item)
+			if err != nil {
+				return err
+			}
+
+			results[i] = result
+			return nil
+		})
+		if goErr != nil {
+			break
+		}
+	}
+
+	err := g.Wait()
+	if err == nil {
+		err = goErr
+	}
+
+	return results, err
 }
 
-var _ Configurer = (*limitConfigurer)(nil)
+// Run returns a new Group that has been configured by applying configurers,
+// launches each of fns on it using Group.Go, and returns the error returned
+// by Group.Wait. If the Group is cancelled before every function has been
+// launched, the remaining functions are skipped.
+func Run(configurers []Configurer, fns ...func() error) error {
+	g := New(configurers...)
+	for _, f := range fns {
+		err := g.Go(f)
+		if err != nil {
+			break
+		}
+	}
 
-func (c limitConfigurer) configure(group *Group) {
-	group.semaphore = make(chan struct{}, c.limit)
+	return g.Wait()
 }
 
-// WithLimit returns a Configurer that configures a Group to keep the number
-// of goroutines managed by the Group at or below the limit.
-func WithLimit(limit uint) Configurer {
-	return &limitConfigurer{limit: limit}
+// ForEach launches f on each of items using Group.Go, passing it the index
+// of the item alongside the item itself, and waits for them to finish. If
+// Group.Go returns an error, such as a CancelError because g has been
+// cancelled, no further items are launched, and once the items that were
+// launched have finished, ForEach returns that error. Otherwise, ForEach
+// returns the error returned by Group.Wait.
+func ForEach[T any](g *Group, items []T, f func(i int, item T) error) error {
+	var goErr error
+	for i, item := range items {
+		goErr = g.Go(func() error {
+			return f(i, item)
+		})
+		if goErr != nil {
+			break
+		}
+	}
+
+	err := g.Wait()
+	if goErr != nil {
+		return goErr
+	}
+
+	return err
+}
+
+// RunFailFastResults runs each of fs concurrently, passing each a
+// context.Context derived from ctx that is cancelled as soon as any of fs
+// returns a non-nil error, and returns their results in the same order as
+// fs alongside the first error that occurred.
+//
+// If an error occurs, only the results of the functions that succeeded
+// before the context.Context was cancelled are present; the rest are the
+// zero value of T.
+func RunFailFastResults[T any](ctx context.Context, fs ...func(context.Context) (T, error)) ([]T, error) {
+	ctx, cc := WithCancel(ctx)
+	var (
+		eg      = New(cc)
+		results = make([]T, len(fs))
+	)
+	for i, f := range fs {
+		_ = eg.Go(func() error {
+			result, err := f(ctx)
+			if err != nil {
+				return err
+			}
+
+			// A function that succeeded only after the context.Context
+			// was cancelled did not succeed before the error occurred.
+			if ctx.Err() == nil {
+				results[i] = result
+			}
+			return nil
+		})
+	}
+	_ = eg.Wait()
+
+	eg.errLock.Lock()
+	defer eg.errLock.Unlock()
+	return results, eg.firstErr
+}
+
+// SetLimit changes the limit on the number of goroutines managed by the
+// Group. It is safe to call while goroutines are running: raising the limit
+// immediately allows more goroutines to be launched, while lowering it
+// leaves running goroutines unaffected, with new launches blocking until
+// enough of them have finished to bring the number below the new limit.
+// Calling SetLimit(0) therefore blocks all new launches until the limit is
+// raised again.
+//
+// If the Group previously had no limit, goroutines that are already running
+// do not count towards the new limit.
+func (g *Group) SetLimit(limit uint) {
+	for {
+		sem := g.semaphore.Load()
+		if sem != nil {
+			sem.resize(int64(limit))
+			return
+		}
+
+		if g.semaphore.CompareAndSwap(nil, newSemaphore(int64(limit))) {
+			return
+		}
+	}
+}
+
+// GoFinal registers f to be launched in another goroutine once all other
+// goroutines managed by the Group have finished executing, the next time
+// Group.Wait is called. Functions registered with GoFinal run concurrently
+// with each other, and any errors they return are aggregated alongside the
+// errors returned by the other functions passed to the Group.
+func (g *Group) GoFinal(f func() error) {
+	g.finalLock.Lock()
+	defer g.finalLock.Unlock()
+	g.finals = append(g.finals, f)
+}
+
+// GoOnce behaves like Group.Go for the first call with a given key over the
+// lifetime of the Group, and does nothing for any later calls with the same
+// key. If launching f fails, a later call with the same key may try again.
+func (g *Group) GoOnce(key string, f func() error) error {
+	g.onceLock.Lock()
+	if _, ok := g.onceKeys[key]; ok {
+		g.onceLock.Unlock()
+		return nil
+	}
+
+	if g.onceKeys == nil {
+		g.onceKeys = make(map[string]struct{})
+	}
+	g.onceKeys[key] = struct{}{}
+	g.onceLock.Unlock()
+
+	err := g.Go(f)
+	if err != nil {
+		g.onceLock.Lock()
+		delete(g.onceKeys, key)
+		g.onceLock.Unlock()
+	}
+
+	return err
+}
+
+// GoUnlessBusy launches f in another goroutine unless doing so would cause
+// the number of goroutines managed by the Group to exceed its limit, and
+// reports whether f was launched. If the Group has been cancelled, an error
+// is returned.
+//
+// Whether the Group is busy is decided at the moment GoUnlessBusy is
+// called, so the decision may already be stale by the time it returns.
+func (g *Group) GoUnlessBusy(f func() error) (bool, error) {
+	err := g.TryGo(f)
+	if err != nil {
+		var le *LimitError
+		if errors.As(err, &le) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (g *Group) check() error {
+	if g.warmupErr != nil {
+		g.skipped.Add(1)
+		return g.warmupErr
+	}
+
+	if g.strict && g.waited.Load() {
+		g.skipped.Add(1)
+		return &WaitedError{}
+	}
+
+	if g.sealed.Load() {
+		g.skipped.Add(1)
+		return &SealedError{}
+	}
+
+	if g.cancelled.Load() {
+		g.skipped.Add(1)
+		return g.cancelError()
+	}
+
+	return nil
+}
+
+// done returns a channel that is closed when the context.Context derived by
+// WithCancel is cancelled, or, if the Group was not configured using it,
+// when the Group is cancelled.
+func (g *Group) done() <-chan struct{} {
+	if g.ctx != nil {
+		return g.ctx.Done()
+	}
+
+	g.cancelLock.Lock()
+	defer g.cancelLock.Unlock()
+
+	if g.cancelCh == nil {
+		g.cancelCh = make(chan struct{})
+	}
+
+	return g.cancelCh
+}
+
+// closeCancelCh closes the channel returned by Group.done for a Group that
+// was not configured using WithCancel.
+func (g *Group) closeCancelCh() {
+	g.cancelLock.Lock()
+	defer g.cancelLock.Unlock()
+
+	if g.cancelCh == nil {
+		g.cancelCh = make(chan struct{})
+	}
+
+	select {
+	case <-g.cancelCh:
+	default:
+		close(g.cancelCh)
+	}
+}
+
+// acquire blocks until a slot of sem is acquired, returning true, or until
+// stop is closed, returning false.
+func (g *Group) acquire(sem *semaphore, stop <-chan struct{}) bool {
+	return g.acquireN(sem, 1, stop)
+}
+
+// acquireN blocks until n units of sem are acquired, returning true, or
+// until stop is closed, returning false.
+func (g *Group) acquireN(sem *semaphore, n int64, stop <-chan struct{}) bool {
+	for range g.spin {
+		if sem.tryAcquire(n) {
+			return true
+		}
+
+		runtime.Gosched()
+	}
+
+	if sem.tryAcquire(n) {
+		return true
+	}
+
+	g.blocked.Add(1)
+	defer g.blocked.Add(-1)
+	return sem.acquire(n, stop)
+}
+
+// doGo launches f in another goroutine, or hands it to the worker pool of
+// the Group, releasing a slot of sem once it has finished executing. sem may
+// be nil.
+func (g *Group) doGo(f func() error, sem *semaphore) {
+	g.launch(task{f: f, sem: sem})
+}
+
+// task is a function passed to a Group, along with everything needed to
+// execute it. It is passed by value, so that launching a function on the
+// worker pool of a Group does not allocate.
+type task struct {
+	group *Group
+
+	// Exactly one of f and ctxF is set. ctxF is passed the context.Context
+	// of the task.
+	f    func() error
+	ctxF func(context.Context) error
+
+	// finish, if set, is called with the outcome of the function, once any
+	// retries, transformation and recovery have been applied, before the
+	// Group stops waiting for it.
+	finish func(error)
+
+	sem        *semaphore
+	index      int64
+	prev, next chan struct{}
+}
+
+// launch implements Group.doGo, executing t in another goroutine or on the
+// worker pool of the Group.
+func (g *Group) launch(t task) {
+	t.group = g
+	if g.serial {
+		t.next = make(chan struct{})
+
+		g.serialLock.Lock()
+		t.prev, g.serialTail = g.serialTail, t.next
+		g.serialLock.Unlock()
+	}
+
+	g.startOnce.Do(func() {
+		g.start = time.Now()
+	})
+
+	g.wg.Add(1)
+	t.index = g.launched.Add(1)
+	active := g.active.Add(1)
+	if active == 1 {
+		g.rearmDone()
+	}
+	for {
+		peak := g.peak.Load()
+		if active <= peak || g.peak.CompareAndSwap(peak, active) {
+			break
+		}
+	}
+
+	if g.pool != nil {
+		g.pool.submit(t)
+		return
+	}
+
+	go t.execute()
+}
+
+// execute runs the function of t, recording its outcome with the Group.
+func (t task) execute() {
+	g := t.group
+
+	var err error
+	defer func() {
+		if t.finish != nil {
+			t.finish(err)
+		}
+
+		remaining := g.active.Add(-1)
+		g.completed.Add(1)
+		g.wg.Done()
+
+		if remaining == 0 {
+			g.closeDone()
+		}
+
+		if t.sem != nil {
+			t.sem.release(1)
+		}
+
+		if g.onRelease != nil {
+			g.callHook(g.onRelease)
+		}
+	}()
+
+	if g.serial {
+		if t.prev != nil {
+			_ = <-t.prev
+		}
+		defer close(t.next)
+	}
+
+	ctx := g.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if g.escalation != nil {
+		parent := ctx
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(parent, g.escalation.d)
+		defer cancel()
+
+		// Only the timeout firing counts as a breach, not the
+		// cancellation of parent or the task returning. A breach that
+		// is already being counted finishes before the task does.
+		breached := make(chan struct{})
+		stop := context.AfterFunc(ctx, func() {
+			defer close(breached)
+			if parent.Err() == nil {
+				g.breach()
+			}
+		})
+		defer func() {
+			if !stop() {
+				_ = <-breached
+			}
+		}()
+	}
+
+	if g.observer != nil {
+		g.callHook(g.observer.onStart)
+
+		began := time.Now()
+		defer func() {
+			d := time.Since(began)
+			g.callHook(func() {
+				g.observer.onFinish(err, d)
+			})
+		}()
+	}
+
+	if g.tracer != nil {
+		var span trace.Span
+		ctx, span = g.tracer.tracer.Start(ctx, g.tracer.spanName)
+		defer func() {
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			span.End()
+		}()
+	}
+
+	call := t.f
+	if t.ctxF != nil {
+		call = func() error {
+			return t.ctxF(ctx)
+		}
+	}
+	for i := len(g.mws) - 1; i >= 0; i-- {
+		call = g.mws[i](call)
+	}
+
+	err = g.attempt(call)
+	for retry := uint(0); err != nil && retry < g.retries; retry++ {
+		if g.cancelled.Load() || !g.sleep(retry) {
+			break
+		}
+
+		err = g.attempt(call)
+	}
+
+	if err != nil && g.transform != nil {
+		transformed := g.transform(err)
+		if transformed == nil {
+			// The function still failed, so count it and report its
+			// original error to finish, but neither aggregate it nor
+			// let it count as a success.
+			g.failed.Add(1)
+			return
+		}
+
+		err = transformed
+	}
+
+	if err != nil && g.ignores(err) {
+		return
+	}
+
+	if g.adaptive != nil {
+		g.adaptive.observe(g, err == nil)
+	}
+
+	if err != nil {
+		g.failed.Add(1)
+		if g.errWriter != nil {
+			g.errWriter.write(err)
+		}
+
+		if g.logger != nil {
+			attrs := []any{slog.Any("error", err)}
+
+			var le *labeledError
+			if errors.As(err, &le) {
+				attrs = append(attrs, slog.String("label", le.label))
+			}
+
+			g.logger.Warn("errgroup: task failed", attrs...)
+		}
+
+		if g.onError != nil {
+			g.callHook(func() {
+				g.onError(err)
+			})
+		}
+
+		switch {
+		case g.cancelled.Load():
+		case g.cancel == nil, g.firstSuccess:
+			g.record(t.index, err)
+		case g.cancelOn != nil && !g.cancelOn(err):
+			g.record(t.index, err)
+		case g.maxErrors > 0:
+			count := g.record(t.index, err)
+			if count >= int64(g.maxErrors) && g.cancelled.CompareAndSwap(false, true) {
+				g.setTrigger(t.index, err)
+				g.doCancel(err)
+			}
+		case g.debounce > 0:
+			g.record(t.index, err)
+			g.scheduleCancel(t.index, err)
+		case g.cancelled.CompareAndSwap(false, true):
+			g.record(t.index, err)
+			g.setTrigger(t.index, err)
+			g.doCancel(err)
+		}
+	} else {
+		switch {
+		case g.firstSuccess:
+			g.succeeded.Store(true)
+			if g.cancel != nil && g.cancelled.CompareAndSwap(false, true) {
+				g.doCancel(nil)
+			}
+		case g.debounce > 0:
+			g.abortCancel()
+		}
+	}
+}
+
+func (g *Group) breach() {
+	breaches := g.escalation.breaches.Add(1)
+	if breaches >= int64(g.escalation.maxBreaches) && g.cancelled.CompareAndSwap(false, true) {
+		g.doCancel(nil)
+	}
+}
+
+func (g *Group) scheduleCancel(index int64, cause error) {
+	g.debounceLock.Lock()
+	defer g.debounceLock.Unlock()
+
+	if g.debounceTimer != nil {
+		return
+	}
+
+	var timer *time.Timer
+	timer = time.AfterFunc(g.debounce, func() {
+		g.debounceLock.Lock()
+		current := g.debounceTimer == timer
+		g.debounceTimer = nil
+		g.debounceLock.Unlock()
+
+		if current && g.cancelled.CompareAndSwap(false, true) {
+			g.setTrigger(index, cause)
+			g.doCancel(cause)
+		}
+	})
+	g.debounceTimer = timer
+}
+
+func (g *Group) abortCancel() {
+	g.debounceLock.Lock()
+	defer g.debounceLock.Unlock()
+
+	if g.debounceTimer != nil {
+		g.debounceTimer.Stop()
+		g.debounceTimer = nil
+	}
+}
+
+func (g *Group) run(f func() error) (err error) {
+	if g.recover {
+		defer func() {
+			v := recover()
+			if v != nil {
+				err = &PanicError{
+					Value: v,
+					Stack: debug.Stack(),
+				}
+			}
+		}()
+	}
+
+	return f()
+}
+
+// record aggregates err, returned by the function launched at index,
+// returning the number of errors that have been aggregated so far.
+func (g *Group) record(index int64, err error) int64 {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+
+	count := g.errCount.Load()
+	if g.cancel != nil && g.maxErrors > 0 && count >= int64(g.maxErrors) {
+		return count
+	}
+
+	g.appendErr(index, err)
+	if g.firstErr == nil {
+		g.firstErr = err
+		if g.firstErrCh != nil {
+			g.firstErrCh <- err
+			close(g.firstErrCh)
+		}
+	}
+
+	return g.errCount.Add(1)
+}
+
+// appendErr aggregates err, returned by the function launched at index. The
+// caller must hold errLock.
+func (g *Group) appendErr(index int64, err error) {
+	if g.firstOnly {
+		if g.err == nil {
+			g.err = err
+		}
+
+		return
+	}
+
+	if g.ordered {
+		g.orderedErrs = append(g.orderedErrs, orderedError{index: index, err: err})
+	}
+
+	g.err = g.join(g.err, err)
+}
+
+// join aggregates err into existing using the function supplied to
+// WithErrorJoiner, or multierr.Append if the Group was not configured using
+// it.
+func (g *Group) join(existing, err error) error {
+	if g.joiner != nil {
+		return g.joiner(existing, err)
+	}
+
+	return multierr.Append(existing, err)
+}
+
+// sortErrs rebuilds the aggregated error so that the errors it aggregates
+// are in the order that the functions which returned them were launched. The
+// caller must hold errLock.
+func (g *Group) sortErrs() {
+	slices.SortStableFunc(g.orderedErrs, func(a, b orderedError) int {
+		return cmp.Compare(a.index, b.index)
+	})
+
+	g.err = nil
+	for _, oe := range g.orderedErrs {
+		g.err = g.join(g.err, oe.err)
+	}
+}
+
+// setTrigger records that the cancellation of the Group was triggered by
+// err, returned by the function launched at index.
+func (g *Group) setTrigger(index int64, err error) {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+
+	g.trigger = &CancelError{err: err, index: int(index - 1)}
+}
+
+// cancelError returns a CancelError that wraps the error that triggered the
+// cancellation of the Group, if any.
+func (g *Group) cancelError() *CancelError {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+
+	if g.trigger == nil {
+		return &CancelError{}
+	}
+
+	ce := *g.trigger
+	return &ce
+}
+
+// doCancel cancels the Group once it has been marked as cancelled, closing
+// any io.Closer's and cancelling any linked groups. If cause is non-nil, it
+// is recorded as the cause of the cancellation.
+func (g *Group) doCancel(cause error) {
+	g.aborted.Store(true)
+	if g.cancel != nil {
+		g.cancel(cause)
+	}
+	g.closeCancelCh()
+
+	if g.logger != nil {
+		g.logger.Info("errgroup: group cancelled", slog.Any("cause", cause))
+	}
+
+	g.closeOnce.Do(g.close)
+
+	g.linkLock.Lock()
+	linked := g.linked
+	g.linkLock.Unlock()
+
+	for _, group := range linked {
+		if group.cancelled.CompareAndSwap(false, true) {
+			group.doCancel(cause)
+		}
+	}
+}
+
+func (g *Group) close() {
+	for i := len(g.closers) - 1; i >= 0; i-- {
+		err := g.closers[i].Close()
+		if err != nil {
+			g.errLock.Lock()
+			g.appendErr(math.MaxInt64, err)
+			g.errLock.Unlock()
+		}
+	}
+}
+
+// LinkCancellation links the cancellation of groups, so that when any one of
+// them is cancelled, the rest are cancelled too. Each Group continues to
+// aggregate its own errors. If any of groups has already been cancelled, the
+// rest are cancelled immediately.
+func LinkCancellation(groups ...*Group) {
+	for _, group := range groups {
+		group.linkLock.Lock()
+		for _, other := range groups {
+			if other != group {
+				group.linked = append(group.linked, other)
+			}
+		}
+		group.linkLock.Unlock()
+	}
+
+	for _, group := range groups {
+		if group.cancelled.Load() {
+			group.doCancel(nil)
+			break
+		}
+	}
+}
+
+// Wait blocks until all goroutines managed by the Group have finished
+// executing and returns an error that aggregates any errors that occurred
+// within each goroutine.
+//
+// Wait may be called from multiple goroutines at once, in which case each of
+// them returns the same error once all goroutines have finished executing.
+func (g *Group) Wait() error {
+	g.waited.Store(true)
+
+	if g.heartbeat != nil {
+		stop := g.startHeartbeat()
+		defer stop()
+	}
+
+	// Concurrent calls to Wait take turns, so that one of them cannot return
+	// before the finals launched by another have finished executing.
+	g.waitLock.Lock()
+	err := g.waitTasks()
+	if err != nil {
+		g.waitLock.Unlock()
+		return err
+	}
+
+	g.finalLock.Lock()
+	finals := g.finals
+	g.finals = nil
+	g.finalLock.Unlock()
+
+	if len(finals) > 0 {
+		for _, f := range finals {
+			sem := g.semaphore.Load()
+			if sem != nil {
+				g.acquire(sem, nil)
+			}
+
+			g.doGo(f, sem)
+		}
+
+		err := g.waitTasks()
+		if err != nil {
+			g.waitLock.Unlock()
+			return err
+		}
+	}
+
+	if g.pool != nil {
+		g.pool.stop()
+	}
+	g.waitLock.Unlock()
+
+	if g.lifetime != nil {
+		g.lifetime.Stop()
+	}
+
+	if g.cancel != nil {
+		g.cancelOnce.Do(func() {
+			// Cancellation inherited from the parent context.Context
+			// happened before, rather than because of, this call to Wait.
+			if g.ctx.Err() != nil {
+				g.aborted.Store(true)
+			}
+
+			g.cancel(nil)
+		})
+	}
+
+	g.waitCtxLock.Lock()
+	g.waitCtxDone = true
+	if g.waitCancel != nil {
+		g.waitCancel()
+	}
+	g.waitCtxLock.Unlock()
+
+	// A Group configured using WithFirstError keeps only the first error, so
+	// there is nothing to order.
+	if g.ordered && !g.firstOnly {
+		g.errLock.Lock()
+		g.sortErrs()
+		g.errLock.Unlock()
+	}
+
+	err = g.result()
+	if g.logger != nil {
+		g.logger.Info(
+			"errgroup: wait completed",
+			slog.Int64("tasks", g.completed.Load()),
+			slog.Int64("errors", g.failed.Load()),
+		)
+	}
+
+	return err
+}
+
+// waitTasks blocks until all goroutines managed by the Group have finished
+// executing. If the Group has been configured using WithDeadlockTimeout, it
+// instead gives up and returns a DeadlockError if none of them finish
+// executing for the configured duration.
+func (g *Group) waitTasks() error {
+	if g.deadlock <= 0 {
+		g.wg.Wait()
+		return nil
+	}
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	ticker := time.NewTicker(g.deadlock)
+	defer ticker.Stop()
+
+	completed := g.completed.Load()
+	for {
+		select {
+		case <-done:
+			return nil
+		case <-ticker.C:
+			current := g.completed.Load()
+			if current == completed {
+				return &DeadlockError{
+					timeout: g.deadlock,
+					active:  int(g.active.Load()),
+				}
+			}
+
+			completed = current
+		}
+	}
+}
+
+// TryWait reports whether all goroutines managed by the Group have finished
+// executing without blocking. If they have, TryWait also returns the error
+// that Group.Wait would return. Unlike Group.Wait, TryWait leaves the state
+// of the Group untouched, so the Group can still be waited on afterwards.
+func (g *Group) TryWait() (error, bool) {
+	select {
+	case <-g.Done():
+		return g.result(), true
+	default:
+		return nil, false
+	}
+}
+
+// attempt makes a single attempt at calling f, unless the Group injects a
+// fault in its place.
+func (g *Group) attempt(f func() error) error {
+	if g.fault != nil && g.fault.inject() {
+		return g.fault.err
+	}
+
+	return g.run(f)
+}
+
+// sleep waits for the backoff that precedes the given retry, returning true,
+// or until the Group is cancelled, returning false.
+func (g *Group) sleep(retry uint) bool {
+	if g.backoff == nil {
+		return true
+	}
+
+	d := g.backoff.delay(retry)
+	if g.jitter != nil {
+		d = g.jitter.apply(d)
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-g.done():
+		return false
+	}
+}
+
+// result returns the error that aggregates the errors returned by the
+// functions passed to the Group.
+func (g *Group) result() error {
+	if g.succeeded.Load() {
+		return nil
+	}
+
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+	return g.err
+}
+
+// Report summarises the outcome of the goroutines managed by a Group.
+type Report struct {
+	// Err aggregates any errors that occurred within each goroutine.
+	Err error
+
+	// Submitted is the number of functions that were launched.
+	Submitted int64
+
+	// Succeeded is the number of functions that returned a nil error.
+	Succeeded int64
+
+	// Failed is the number of functions that returned a non-nil error.
+	Failed int64
+
+	// Skipped is the number of functions that were rejected rather than
+	// launched: because the Group had been cancelled, including while they
+	// were waiting for the limit or for the Group to resume, because the
+	// Group had been sealed, because Group.Wait had already been called on a
+	// Group configured using WithStrictLifecycle, or because the warmup
+	// function supplied to WithWarmup had failed.
+	Skipped int64
+
+	// Duration is the time elapsed between the first function being
+	// launched and Group.WaitReport returning.
+	Duration time.Duration
+
+	// MaxConcurrency is the largest number of goroutines that were active
+	// at once.
+	MaxConcurrency int
+}
+
+// WaitReport behaves like Group.Wait, but returns a Report summarising the
+// outcome of the goroutines managed by the Group.
+func (g *Group) WaitReport() Report {
+	err := g.Wait()
+
+	var duration time.Duration
+	if !g.start.IsZero() {
+		duration = time.Since(g.start)
+	}
+
+	var (
+		completed = g.completed.Load()
+		failed    = g.failed.Load()
+	)
+	return Report{
+		Err:            err,
+		Submitted:      completed,
+		Succeeded:      completed - failed,
+		Failed:         failed,
+		Skipped:        g.skipped.Load(),
+		Duration:       duration,
+		MaxConcurrency: g.MaxConcurrency(),
+	}
+}
+
+// Stats is a point-in-time snapshot of the counters maintained by a Group.
+type Stats struct {
+	// Launched is the number of functions that have been launched.
+	Launched int64
+
+	// Completed is the number of functions that have finished executing.
+	Completed int64
+
+	// Failed is the number of functions that returned a non-nil error.
+	Failed int64
+
+	// Active is the number of goroutines that are currently executing.
+	Active int
+
+	// Limit is the current limit of the Group, or zero if it has none.
+	Limit int
+
+	// Cancelled reports whether the Group has been cancelled.
+	Cancelled bool
+}
+
+// Stats returns a snapshot of the counters maintained by the Group, without
+// waiting for the goroutines managed by the Group to finish executing.
+func (g *Group) Stats() Stats {
+	var limit int
+	if sem := g.semaphore.Load(); sem != nil {
+		_, l := sem.size()
+		limit = int(l)
+	}
+
+	return Stats{
+		Limit:     limit,
+		Launched:  g.launched.Load(),
+		Completed: g.completed.Load(),
+		Failed:    g.failed.Load(),
+		Active:    int(g.active.Load()),
+		Cancelled: g.cancelled.Load(),
+	}
+}
+
+// WaitContext behaves like Group.Wait, except that if ctx is done before all
+// goroutines managed by the Group have finished executing, it returns a
+// WaitInterruptedError that wraps ctx.Err(). The goroutines are not stopped
+// and continue to run in the background.
+func (g *Group) WaitContext(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- g.Wait()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return &WaitInterruptedError{err: ctx.Err()}
+	}
+}
+
+// Reset prepares the Group to run another batch of goroutines after a call
+// to Group.Wait has returned. It discards any aggregated errors, clears the
+// cancelled state of the Group and zeroes the counters reported by
+// Group.Stats and Group.WaitReport, while keeping its configuration. If the
+// Group was configured using WithCancel, a fresh context.Context is derived
+// from the one supplied to WithCancel, which is the context.Context passed
+// to functions launched by Group.GoCtx from then on. If the Group was
+// configured using WithMaxLifetime, its lifetime starts again. If the Group
+// was configured using WithWarmup, the warmup function is called again, and
+// the Group starts in a cancelled state if it fails. The io.Closer's
+// configured using WithCloseOnCancel are closed at most once over the
+// lifetime of the Group, so once closed, a cancellation after Reset does not
+// close them again.
+//
+// Reset must only be called when no goroutines managed by the Group are
+// running. Calling it concurrently with running goroutines is a programming
+// error.
+func (g *Group) Reset() {
+	g.errLock.Lock()
+	g.err = nil
+	g.firstErr = nil
+	g.firstErrCh = nil
+	g.errCount.Store(0)
+	g.orderedErrs = nil
+	g.trigger = nil
+	g.errLock.Unlock()
+
+	g.doneLock.Lock()
+	g.doneCh = nil
+	g.doneClosed = false
+	g.doneLock.Unlock()
+
+	g.waitCtxLock.Lock()
+	g.waitCtx = nil
+	g.waitCancel = nil
+	g.waitCtxDone = false
+	g.waitCtxLock.Unlock()
+
+	if g.parent != nil {
+		g.setCancel(context.WithCancelCause(g.parent))
+	}
+
+	if g.escalation != nil {
+		g.escalation.breaches.Store(0)
+	}
+
+	if g.lifetime != nil {
+		g.lifetime.Stop()
+		g.armLifetime()
+	}
+
+	if g.debounce > 0 {
+		g.abortCancel()
+	}
+
+	g.launched.Store(0)
+	g.completed.Store(0)
+	g.failed.Store(0)
+	g.skipped.Store(0)
+	g.peak.Store(0)
+	g.startOnce = sync.Once{}
+	g.start = time.Time{}
+
+	g.cancelLock.Lock()
+	g.cancelCh = nil
+	g.cancelLock.Unlock()
+
+	g.cancelOnce = sync.Once{}
+	g.succeeded.Store(false)
+	g.sealed.Store(false)
+	g.aborted.Store(false)
+	g.waited.Store(false)
+	g.cancelled.Store(false)
+
+	g.runWarmup()
+}
+
+// Cancel cancels the Group, so that passing a function to the Group
+// afterwards returns a CancelError. If the Group has been configured using
+// WithCancel, the derived context.Context is cancelled too, along with
+// anything else that is cancelled alongside the Group, such as io.Closer's
+// configured using WithCloseOnCancel. Calling Cancel on a Group that has
+// already been cancelled has no effect.
+func (g *Group) Cancel() {
+	if g.cancelled.CompareAndSwap(false, true) {
+		g.doCancel(nil)
+	}
+}
+
+// Cancelled reports whether the Group was cancelled before it finished, such
+// as by a function passed to it returning a non-nil error, a call to
+// Group.Cancel, or the cancellation of the context.Context passed to
+// WithCancel. The cancellation of the derived context.Context that happens
+// when Group.Wait returns does not count, so after Group.Wait has returned,
+// Cancelled distinguishes a Group whose functions all had the chance to
+// complete from one that was cut short.
+func (g *Group) Cancelled() bool {
+	return g.aborted.Load()
+}
+
+// Pause stops the Group from launching any more functions until
+// Group.Resume is called. While the Group is paused, Group.Go and the
+// variants of it that block, such as Group.GoWithStop, Group.GoWithContext
+// and Group.GoUrgent, block until the Group is resumed or cancelled, and
+// Group.TryGo returns a PausedError. Functions that have already been
+// launched are unaffected, so Group.Wait can still return while the Group
+// is paused. Calling Pause on a Group that is already paused has no effect.
+func (g *Group) Pause() {
+	g.pauseLock.Lock()
+	defer g.pauseLock.Unlock()
+
+	if g.pauseCh == nil {
+		g.pauseCh = make(chan struct{})
+	}
+}
+
+// Resume resumes a Group that was paused by Group.Pause, unblocking any
+// callers of Group.Go waiting for it to be resumed. Calling Resume on a
+// Group that is not paused has no effect.
+func (g *Group) Resume() {
+	g.pauseLock.Lock()
+	defer g.pauseLock.Unlock()
+
+	if g.pauseCh != nil {
+		close(g.pauseCh)
+		g.pauseCh = nil
+	}
+}
+
+// paused returns a channel that is closed when the Group is resumed, or nil
+// if the Group is not paused.
+func (g *Group) paused() <-chan struct{} {
+	g.pauseLock.Lock()
+	defer g.pauseLock.Unlock()
+	return g.pauseCh
+}
+
+// awaitResume blocks until the Group is not paused, returning true, or until
+// stop is closed, returning false.
+func (g *Group) awaitResume(stop <-chan struct{}) bool {
+	resumed := g.paused()
+	if resumed == nil {
+		return true
+	}
+
+	select {
+	case <-resumed:
+		return true
+	case <-stop:
+		return false
+	}
+}
+
+// Seal stops the Group from accepting any more functions, so that passing a
+// function to the Group afterwards returns a SealedError. Functions that
+// were passed to the Group beforehand, including any still waiting for the
+// number of goroutines managed by the Group to fall below its limit, are
+// unaffected, and their errors are aggregated by Group.Wait as usual.
+//
+// Unlike the cancellation that follows an error in a Group configured using
+// WithCancel, sealing neither cancels the derived context.Context nor causes
+// the errors of functions that are still executing to be discarded. It only
+// signals that no more work is coming.
+func (g *Group) Seal() {
+	g.sealed.Store(true)
+}
+
+// Drain seals the Group, as though by Group.Seal, and then waits for all of
+// the functions that were passed to it beforehand to finish, including any
+// still waiting for the number of goroutines managed by the Group to fall
+// below its limit, returning the aggregated error as Group.Wait does. Once
+// Drain has been called, passing a function to the Group returns a
+// SealedError.
+func (g *Group) Drain() error {
+	g.Seal()
+	return g.Wait()
+}
+
+// Done returns a channel that is closed once all goroutines managed by the
+// Group have finished executing, making it a channel based alternative to
+// Group.Wait. If no goroutines are executing when Done is called, the
+// channel is closed immediately. Calling Done multiple times returns the
+// same channel, until a function is launched after the channel has been
+// closed, from which point Done returns a new channel that is closed once
+// that function, and any launched alongside it, have finished executing.
+func (g *Group) Done() <-chan struct{} {
+	g.doneLock.Lock()
+	defer g.doneLock.Unlock()
+
+	if g.doneCh == nil {
+		g.doneCh = make(chan struct{})
+	}
+
+	if !g.doneClosed && g.active.Load() == 0 {
+		close(g.doneCh)
+		g.doneClosed = true
+	}
+
+	return g.doneCh
+}
+
+// rearmDone discards the channel returned by Group.Done if it has been
+// closed, so that the next call to Group.Done returns a new one. It is
+// called when a function is launched while no goroutines are executing.
+func (g *Group) rearmDone() {
+	g.doneLock.Lock()
+	defer g.doneLock.Unlock()
+
+	if g.doneClosed {
+		g.doneCh = nil
+		g.doneClosed = false
+	}
+}
+
+// closeDone closes the channel returned by Group.Done, if it has been
+// created, once no goroutines are executing.
+func (g *Group) closeDone() {
+	g.doneLock.Lock()
+	defer g.doneLock.Unlock()
+
+	if g.doneCh != nil && !g.doneClosed && g.active.Load() == 0 {
+		close(g.doneCh)
+		g.doneClosed = true
+	}
+}
+
+// Context returns the context.Context that the Group operates under. If the
+// Group was configured using WithCancel, this is the derived
+// context.Context, which is cancelled the first time a function passed to
+// the Group returns a non-nil error or a call to Group.Wait returns.
+// Otherwise, it is a context.Context derived from context.Background that
+// is cancelled once a call to Group.Wait has returned. Calling Context
+// multiple times returns the same context.Context.
+func (g *Group) Context() context.Context {
+	if g.ctx != nil {
+		return g.ctx
+	}
+
+	g.waitCtxLock.Lock()
+	defer g.waitCtxLock.Unlock()
+
+	if g.waitCtx == nil {
+		g.waitCtx, g.waitCancel = context.WithCancel(context.Background())
+		if g.waitCtxDone {
+			g.waitCancel()
+		}
+	}
+
+	return g.waitCtx
+}
+
+func (g *Group) startHeartbeat() func() {
+	var (
+		ticker = time.NewTicker(g.heartbeat.interval)
+		done   = make(chan struct{})
+		exited = make(chan struct{})
+	)
+	go func() {
+		defer close(exited)
+
+		for {
+			select {
+			case <-ticker.C:
+				active := int(g.active.Load())
+				g.callHook(func() {
+					g.heartbeat.f(active)
+				})
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	stop := func() {
+		ticker.Stop()
+		close(done)
+		<-exited
+	}
+	return stop
+}
+
+// FirstErrorChan returns a channel that receives the first error returned
+// by a function passed to the Group, and is then closed. If no function
+// returns an error, the channel is never closed. Calling FirstErrorChan
+// multiple times returns the same channel.
+func (g *Group) FirstErrorChan() <-chan error {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+
+	if g.firstErrCh == nil {
+		g.firstErrCh = make(chan error, 1)
+		if g.firstErr != nil {
+			g.firstErrCh <- g.firstErr
+			close(g.firstErrCh)
+		}
+	}
+
+	return g.firstErrCh
+}
+
+// Errors returns a snapshot of the errors that have been aggregated by the
+// Group so far, without waiting for the goroutines managed by the Group to
+// finish executing. If no errors have been aggregated, Errors returns an
+// empty slice.
+func (g *Group) Errors() []error {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+
+	var me *multierr.Error
+	switch {
+	case g.err == nil:
+		return []error{}
+	case errors.As(g.err, &me):
+		return append([]error{}, me.Unwrap()...)
+	default:
+		return []error{g.err}
+	}
+}
+
+// FlushErrors returns an error that aggregates the errors that have been
+// aggregated by the Group so far, and clears them, so that they are
+// reported by neither later calls to FlushErrors nor Group.Wait. If no
+// errors have been aggregated, FlushErrors returns nil.
+func (g *Group) FlushErrors() error {
+	g.errLock.Lock()
+	defer g.errLock.Unlock()
+
+	err := g.err
+	g.err = nil
+	g.orderedErrs = nil
+	return err
+}
+
+// callHook calls a user-supplied hook, recovering from any panic so that it
+// cannot destabilise the Group.
+func (g *Group) callHook(hook func()) {
+	defer func() {
+		v := recover()
+		if v == nil {
+			return
+		}
+
+		if g.hookPanic != nil {
+			g.hookPanic(v)
+			return
+		}
+
+		log.Printf("errgroup: recovered from panic in hook: %v", v)
+	}()
+
+	hook()
+}
+
+// MaxConcurrency returns the largest number of goroutines that have been
+// active at once over the lifetime of the Group.
+func (g *Group) MaxConcurrency() int {
+	return int(g.peak.Load())
+}
+
+// BlockedProducers returns the number of callers that are currently blocked
+// waiting for the number of goroutines managed by the Group to fall below
+// its limit.
+func (g *Group) BlockedProducers() int {
+	return int(g.blocked.Load())
+}
+
+var _ json.Marshaler = (*Group)(nil)
+
+// MarshalJSON returns a JSON encoding of a snapshot of the state of the
+// Group, intended for exposing on debugging endpoints. The snapshot reports
+// the limit of the Group, the number of goroutines that are active, the
+// number of functions that have completed, succeeded and failed, and
+// whether the Group has been cancelled.
+func (g *Group) MarshalJSON() ([]byte, error) {
+	var limit int
+	if sem := g.semaphore.Load(); sem != nil {
+		_, l := sem.size()
+		limit = int(l)
+	}
+
+	var (
+		completed = g.completed.Load()
+		failed    = g.failed.Load()
+	)
+	snapshot := struct {
+		Limit     int   `json:"limit"`
+		Active    int64 `json:"active"`
+		Completed int64 `json:"completed"`
+		Succeeded int64 `json:"succeeded"`
+		Failed    int64 `json:"failed"`
+		Cancelled bool  `json:"cancelled"`
+	}{
+		Limit:     limit,
+		Active:    g.active.Load(),
+		Completed: completed,
+		Succeeded: completed - failed,
+		Failed:    failed,
+		Cancelled: g.cancelled.Load(),
+	}
+	return json.Marshal(snapshot)
+}
+
+// GroupError aggregates the errors that occurred within the goroutines
+// managed by a Group, and provides helpers for querying them.
+type GroupError struct {
+	err error
+}
+
+var _ error = (*GroupError)(nil)
+
+func (e *GroupError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the errors aggregated by the GroupError.
+func (e *GroupError) Unwrap() []error {
+	var me *multierr.Error
+	if errors.As(e.err, &me) {
+		return me.Unwrap()
+	}
+
+	return []error{e.err}
+}
+
+// Count returns the number of errors aggregated by the GroupError.
+func (e *GroupError) Count() int {
+	return len(e.Unwrap())
+}
+
+// Has reports whether any error aggregated by the GroupError matches target,
+// as reported by errors.Is.
+func (e *GroupError) Has(target error) bool {
+	return errors.Is(e, target)
+}
+
+// Filter returns the errors aggregated by the GroupError for which keep
+// returns true.
+func (e *GroupError) Filter(keep func(error) bool) []error {
+	var errs []error
+	for _, err := range e.Unwrap() {
+		if keep(err) {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// WaitTyped behaves like Group.Wait, but returns the aggregated error as a
+// *GroupError. If no errors occurred, WaitTyped returns nil.
+func (g *Group) WaitTyped() *GroupError {
+	err := g.Wait()
+	if err == nil {
+		return nil
+	}
+
+	return &GroupError{err: err}
+}
+
+type cancelConfigurer struct {
+	parent context.Context
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+}
+
+var _ Configurer = (*cancelConfigurer)(nil)
+
+func (c cancelConfigurer) configure(group *Group) {
+	group.parent = c.parent
+	group.setCancel(c.ctx, c.cancel)
+}
+
+func (g *Group) setCancel(ctx context.Context, cancel context.CancelCauseFunc) {
+	g.ctx = ctx
+	g.cancel = func(cause error) {
+		g.cancelled.Store(true)
+		cancel(cause)
+	}
+}
+
+// WithCancel returns context.Context derived from ctx and a Configurer. The
+// returned Configurer configures a Group to cancel the derived
+// context.Context when:
+//
+//   - The first time a function passed to Group.Go returns a non-nil error.
+//   - The first time a call to Group.Wait returns.
+//
+// In the first case, the error is recorded as the cause of the
+// cancellation, and can be retrieved by calling context.Cause on the
+// derived context.Context. Any errors returned by functions once the Group
+// has been cancelled, such as context.Canceled from functions observing the
+// derived context.Context, are discarded, so that the error returned by
+// Group.Wait reports the root cause only once.
+func WithCancel(ctx context.Context) (context.Context, Configurer) {
+	parent := ctx
+	ctx, cancel := context.WithCancelCause(parent)
+	return ctx, &cancelConfigurer{
+		parent: parent,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+type limitConfigurer struct {
+	limit uint
+}
+
+var _ Configurer = (*limitConfigurer)(nil)
+
+func (c limitConfigurer) configure(group *Group) {
+	group.semaphore.Store(newSemaphore(int64(c.limit)))
+}
+
+// WithLimit returns a Configurer that configures a Group to keep the number
+// of goroutines managed by the Group at or below the limit.
+func WithLimit(limit uint) Configurer {
+	return &limitConfigurer{limit: limit}
+}
+
+type weightedLimitConfigurer struct {
+	total int64
+}
+
+var _ Configurer = (*weightedLimitConfigurer)(nil)
+
+func (c weightedLimitConfigurer) configure(group *Group) {
+	group.weighted = newSemaphore(c.total)
+}
+
+// WithWeightedLimit returns a Configurer that configures a Group to keep the
+// combined weight of the functions passed to Group.GoWeighted that are
+// executing at once at or below total.
+func WithWeightedLimit(total int64) Configurer {
+	return &weightedLimitConfigurer{total: total}
+}
+
+type errorTransformConfigurer struct {
+	transform func(error) error
+}
+
+var _ Configurer = (*errorTransformConfigurer)(nil)
+
+func (c errorTransformConfigurer) configure(group *Group) {
+	group.transform = c.transform
+}
+
+// WithErrorTransform returns a Configurer that configures a Group to pass
+// each non-nil error returned by a function through transform before it is
+// aggregated. If transform returns nil, the error is dropped: it is neither
+// aggregated nor causes the Group to be cancelled, but the function is still
+// counted as having failed rather than succeeded, so for example it cannot
+// win the race of a Group configured using WithFirstSuccess. The Future
+// returned by Group.Submit, and the outcome delivered by a TypedGroup, carry
+// the original error of such a function.
+//
+// transform is called from the goroutine that ran the function, so it must
+// be safe for concurrent use.
+func WithErrorTransform(transform func(error) error) Configurer {
+	return &errorTransformConfigurer{transform: transform}
+}
+
+type faultInjector struct {
+	prob float64
+	err  error
+
+	rngLock sync.Mutex
+	rng     *rand.Rand
+}
+
+func (f *faultInjector) inject() bool {
+	f.rngLock.Lock()
+	defer f.rngLock.Unlock()
+	return f.rng.Float64() < f.prob
+}
+
+type faultInjectionConfigurer struct {
+	prob   float64
+	err    error
+	seeded bool
+	seed   uint64
+}
+
+var _ Configurer = (*faultInjectionConfigurer)(nil)
+
+func (c faultInjectionConfigurer) configure(group *Group) {
+	src := rand.NewPCG(rand.Uint64(), rand.Uint64())
+	if c.seeded {
+		src = rand.NewPCG(c.seed, c.seed)
+	}
+
+	group.fault = &faultInjector{
+		prob: c.prob,
+		err:  c.err,
+		rng:  rand.New(src),
+	}
+}
+
+// WithFaultInjection returns a Configurer that configures a Group to replace,
+// with probability prob, the execution of each function passed to the Group
+// with an immediate return of err.
+//
+// WithFaultInjection is intended for testing how callers handle errors and
+// cancellation, and should not be used in production.
+func WithFaultInjection(prob float64, err error) Configurer {
+	return &faultInjectionConfigurer{
+		prob: prob,
+		err:  err,
+	}
+}
+
+// WithSeededFaultInjection behaves like WithFaultInjection, except that the
+// decision of whether to inject a fault is drawn from a random number
+// generator seeded with seed, so that a Group configured with the same seed
+// injects faults into the same functions, provided they are executed in the
+// same order, such as by a Group with a limit of one.
+func WithSeededFaultInjection(prob float64, err error, seed uint64) Configurer {
+	return &faultInjectionConfigurer{
+		prob:   prob,
+		err:    err,
+		seeded: true,
+		seed:   seed,
+	}
+}
+
+type spinConfigurer struct {
+	iterations int
+}
+
+var _ Configurer = (*spinConfigurer)(nil)
+
+func (c spinConfigurer) configure(group *Group) {
+	group.spin = c.iterations
+}
+
+// WithSpinBeforeBlock returns a Configurer that configures a Group to make up
+// to iterations non-blocking attempts, yielding the processor between each
+// one, to acquire a slot before Group.Go blocks waiting for the number of
+// goroutines to fall below its limit. This can reduce the latency of
+// Group.Go when functions are short-lived, at the cost of extra CPU usage.
+func WithSpinBeforeBlock(iterations int) Configurer {
+	return &spinConfigurer{iterations: iterations}
+}
+
+type heartbeat struct {
+	interval time.Duration
+	f        func(active int)
+}
+
+type waitHeartbeatConfigurer struct {
+	interval time.Duration
+	f        func(active int)
+}
+
+var _ Configurer = (*waitHeartbeatConfigurer)(nil)
+
+func (c waitHeartbeatConfigurer) configure(group *Group) {
+	group.heartbeat = &heartbeat{
+		interval: c.interval,
+		f:        c.f,
+	}
+}
+
+// WithWaitHeartbeat returns a Configurer that configures a Group to call f
+// every interval while Group.Wait is blocked, passing it the number of
+// goroutines that are still active. The heartbeat stops before Group.Wait
+// returns.
+func WithWaitHeartbeat(interval time.Duration, f func(active int)) Configurer {
+	return &waitHeartbeatConfigurer{
+		interval: interval,
+		f:        f,
+	}
+}
+
+type closeOnCancelConfigurer struct {
+	closer io.Closer
+}
+
+var _ Configurer = (*closeOnCancelConfigurer)(nil)
+
+func (c closeOnCancelConfigurer) configure(group *Group) {
+	group.closers = append(group.closers, c.closer)
+}
+
+// WithCloseOnCancel returns a Configurer that configures a Group to close c
+// when the Group is cancelled, either because a function passed to
+// Group.Go returned a non-nil error while the Group was configured using
+// WithCancel, or because a group linked to it by LinkCancellation was
+// cancelled.
+//
+// WithCloseOnCancel may be supplied multiple times, in which case the
+// io.Closer's are closed in the reverse order to which they were supplied.
+// Any errors returned by Close are aggregated alongside the errors returned
+// by the functions passed to the Group.
+func WithCloseOnCancel(c io.Closer) Configurer {
+	return &closeOnCancelConfigurer{closer: c}
+}
+
+// WithLimitPerCPU returns a Configurer that configures a Group to keep the
+// number of goroutines managed by the Group at or below factor multiplied by
+// the number of logical CPUs, rounded to the nearest integer. The number of
+// logical CPUs is read when WithLimitPerCPU is called, and the limit is never
+// less than one.
+func WithLimitPerCPU(factor float64) Configurer {
+	limit := math.Round(factor * float64(runtime.NumCPU()))
+	return &limitConfigurer{limit: uint(max(limit, 1))}
+}
+
+type defaultLimitConfigurer struct{}
+
+var _ Configurer = (*defaultLimitConfigurer)(nil)
+
+func (c defaultLimitConfigurer) configure(group *Group) {
+	group.semaphore.Store(newSemaphore(int64(runtime.GOMAXPROCS(0))))
+}
+
+// WithDefaultLimit returns a Configurer that configures a Group to keep the
+// number of goroutines managed by the Group at or below the value of
+// GOMAXPROCS. GOMAXPROCS is read when the Group is configured, so the limit
+// does not track any later changes to it.
+func WithDefaultLimit() Configurer {
+	return &defaultLimitConfigurer{}
+}
+
+type leakCheckConfigurer struct{}
+
+var _ Configurer = (*leakCheckConfigurer)(nil)
+
+func (c leakCheckConfigurer) configure(group *Group) {
+	runtime.SetFinalizer(group, func(group *Group) {
+		if group.launched.Load() > 0 && !group.waited.Load() {
+			log.Printf("errgroup: group garbage collected without Wait being called")
+		}
+	})
+}
+
+// WithLeakCheck returns a Configurer that configures a Group to log a
+// warning if it is garbage collected after functions were passed to it but
+// before Group.Wait was called on it. WithLeakCheck is intended for catching
+// misuse during development. The check relies on runtime.SetFinalizer, so
+// it is best-effort and may never run.
+//
+// A Group cannot be garbage collected while any of its goroutines are still
+// running, since they refer to it, so WithLeakCheck does not detect
+// goroutines that never finish.
+func WithLeakCheck() Configurer {
+	return &leakCheckConfigurer{}
+}
+
+type warmupConfigurer struct {
+	warmup func() error
+}
+
+var _ Configurer = (*warmupConfigurer)(nil)
+
+func (c warmupConfigurer) configure(group *Group) {
+	group.warmup = c.warmup
+}
+
+// WithWarmup returns a Configurer that configures a Group to call warmup
+// synchronously, when the Group is constructed by New and after every other
+// Configurer has been applied, and again whenever the Group is Reset. If
+// warmup returns a non-nil error, the Group starts in a cancelled state:
+// Group.Go and Group.TryGo return that error, and Group.Wait returns an
+// error that aggregates it.
+func WithWarmup(warmup func() error) Configurer {
+	return &warmupConfigurer{warmup: warmup}
+}
+
+type hookPanicHandlerConfigurer struct {
+	handler func(any)
+}
+
+var _ Configurer = (*hookPanicHandlerConfigurer)(nil)
+
+func (c hookPanicHandlerConfigurer) configure(group *Group) {
+	group.hookPanic = c.handler
+}
+
+// WithHookPanicHandler returns a Configurer that configures a Group to pass
+// the value recovered from any panic within a hook, such as the function
+// supplied to WithWaitHeartbeat, to handler. By default, a Group recovers
+// from panics within hooks and logs them.
+func WithHookPanicHandler(handler func(any)) Configurer {
+	return &hookPanicHandlerConfigurer{handler: handler}
+}
+
+type serialConfigurer struct{}
+
+var _ Configurer = (*serialConfigurer)(nil)
+
+func (c serialConfigurer) configure(group *Group) {
+	group.serial = true
+}
+
+// WithSerial returns a Configurer that configures a Group to run the
+// functions passed to it one at a time, in the order in which they were
+// passed. Each function still runs in its own goroutine, so Group.Go does
+// not block waiting for earlier functions to finish. Unlike WithLimit(1),
+// WithSerial guarantees that functions run in submission order.
+func WithSerial() Configurer {
+	return &serialConfigurer{}
+}
+
+type cancelDebounceConfigurer struct {
+	d time.Duration
+}
+
+var _ Configurer = (*cancelDebounceConfigurer)(nil)
+
+func (c cancelDebounceConfigurer) configure(group *Group) {
+	group.debounce = c.d
+}
+
+// WithCancelDebounce returns a Configurer that configures a Group, that has
+// also been configured using WithCancel, to delay cancelling itself by d
+// after a function passed to Group.Go returns a non-nil error. If another
+// function returns a nil error within that window, the cancellation is
+// aborted, and a later error starts a new window.
+//
+// Until the Group is actually cancelled, Group.Go continues to launch
+// functions and any errors they return are aggregated, rather than the
+// Group failing fast on the first error.
+func WithCancelDebounce(d time.Duration) Configurer {
+	return &cancelDebounceConfigurer{d: d}
+}
+
+type timeoutEscalation struct {
+	d           time.Duration
+	maxBreaches int
+	breaches    atomic.Int64
+}
+
+type timeoutEscalationConfigurer struct {
+	d           time.Duration
+	maxBreaches int
+}
+
+var _ Configurer = (*timeoutEscalationConfigurer)(nil)
+
+func (c timeoutEscalationConfigurer) configure(group *Group) {
+	group.escalation = &timeoutEscalation{
+		d:           c.d,
+		maxBreaches: c.maxBreaches,
+	}
+}
+
+// WithTimeoutEscalation returns a Configurer that configures a Group to
+// count each function passed to it that runs for longer than d, and to
+// cancel itself once maxBreaches functions have done so. This treats many
+// slow functions as a sign of a systemic slowdown. Functions launched by
+// Group.GoCtx are also passed a context.Context that is cancelled once d
+// has elapsed, so that they can give up individually; other functions are
+// not interrupted.
+func WithTimeoutEscalation(d time.Duration, maxBreaches int) Configurer {
+	return &timeoutEscalationConfigurer{
+		d:           d,
+		maxBreaches: maxBreaches,
+	}
+}
+
+type middlewareConfigurer struct {
+	mws []func(next func() error) func() error
+}
+
+var _ Configurer = (*middlewareConfigurer)(nil)
+
+func (c middlewareConfigurer) configure(group *Group) {
+	group.mws = append(group.mws, c.mws...)
+}
+
+// WithMiddleware returns a Configurer that configures a Group to wrap each
+// function passed to it with mws before running it. The first middleware is
+// the outermost, so the function passed to the Group is called by the last
+// middleware.
+func WithMiddleware(mws ...func(next func() error) func() error) Configurer {
+	return &middlewareConfigurer{mws: mws}
+}
+
+type errorWriter struct {
+	lock sync.Mutex
+	w    io.Writer
+}
+
+func (w *errorWriter) write(err error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	_, _ = fmt.Fprintln(w.w, err)
+}
+
+type errorWriterConfigurer struct {
+	w io.Writer
+}
+
+var _ Configurer = (*errorWriterConfigurer)(nil)
+
+func (c errorWriterConfigurer) configure(group *Group) {
+	group.errWriter = &errorWriter{w: c.w}
+}
+
+// WithErrorWriter returns a Configurer that configures a Group to write each
+// non-nil error returned by a function passed to it to w, one per line, as
+// it occurs. Writes to w are serialised, and w is never closed by the Group.
+func WithErrorWriter(w io.Writer) Configurer {
+	return &errorWriterConfigurer{w: w}
+}
+
+type urgentOvershootConfigurer struct {
+	n uint
+}
+
+var _ Configurer = (*urgentOvershootConfigurer)(nil)
+
+func (c urgentOvershootConfigurer) configure(group *Group) {
+	group.urgent = newSemaphore(int64(c.n))
+}
+
+// WithUrgentOvershoot returns a Configurer that configures a Group to allow
+// up to n goroutines launched by Group.GoUrgent to exceed the limit of the
+// Group. It has no effect unless the Group has a limit.
+func WithUrgentOvershoot(n uint) Configurer {
+	return &urgentOvershootConfigurer{n: n}
+}
+
+type onReleaseConfigurer struct {
+	onRelease func()
+}
+
+var _ Configurer = (*onReleaseConfigurer)(nil)
+
+func (c onReleaseConfigurer) configure(group *Group) {
+	group.onRelease = c.onRelease
+}
+
+// WithOnRelease returns a Configurer that configures a Group to call
+// onRelease each time a goroutine managed by the Group finishes executing,
+// after it has released its slot. onRelease is called from the goroutine
+// that finished, so it must be safe for concurrent use and should not block.
+func WithOnRelease(onRelease func()) Configurer {
+	return &onReleaseConfigurer{onRelease: onRelease}
+}
+
+type maxLifetimeConfigurer struct {
+	d time.Duration
+}
+
+var _ Configurer = (*maxLifetimeConfigurer)(nil)
+
+func (c maxLifetimeConfigurer) configure(group *Group) {
+	group.maxLifetime = c.d
+	group.armLifetime()
+}
+
+// armLifetime starts the timer that cancels the Group once its maximum
+// lifetime has elapsed.
+func (g *Group) armLifetime() {
+	g.lifetime = time.AfterFunc(g.maxLifetime, func() {
+		if g.cancelled.CompareAndSwap(false, true) {
+			err := &LifetimeExceededError{lifetime: g.maxLifetime}
+			g.record(g.launched.Load(), err)
+			g.doCancel(err)
+		}
+	})
+}
+
+// WithMaxLifetime returns a Configurer that configures a Group to cancel
+// itself once d has elapsed since it was constructed, or since Group.Reset
+// was last called, regardless of whether its goroutines have finished
+// executing. If this happens, Group.Wait returns an error that aggregates a
+// LifetimeExceededError. The timer is stopped when Group.Wait returns.
+//
+// The functions passed to the Group are only interrupted if they observe
+// the context.Context of a Group configured using WithCancel.
+func WithMaxLifetime(d time.Duration) Configurer {
+	return &maxLifetimeConfigurer{d: d}
+}
+
+type recoverConfigurer struct{}
+
+var _ Configurer = (*recoverConfigurer)(nil)
+
+func (c recoverConfigurer) configure(group *Group) {
+	group.recover = true
+}
+
+// WithRecover returns a Configurer that configures a Group to recover from
+// panics within the functions passed to it. A recovered panic is converted
+// into a PanicError, which is then treated like any other error returned by
+// the function.
+func WithRecover() Configurer {
+	return &recoverConfigurer{}
+}
+
+type firstErrorConfigurer struct{}
+
+var _ Configurer = (*firstErrorConfigurer)(nil)
+
+func (c firstErrorConfigurer) configure(group *Group) {
+	group.firstOnly = true
+}
+
+// WithFirstError returns a Configurer that configures a Group to keep only
+// the first error that occurs within its goroutines, discarding the rest.
+// Group.Wait then returns that error as is, rather than an error that
+// aggregates it.
+func WithFirstError() Configurer {
+	return &firstErrorConfigurer{}
+}
+
+type taskTimeoutConfigurer struct {
+	d time.Duration
+}
+
+var _ Configurer = (*taskTimeoutConfigurer)(nil)
+
+func (c taskTimeoutConfigurer) configure(group *Group) {
+	group.taskTimeout = c.d
+}
+
+// WithTaskTimeout returns a Configurer that configures a Group to pass each
+// function launched by Group.GoCtx a context.Context that is cancelled once
+// d has elapsed since the function started. If the function returns the
+// error of that context.Context, it is aggregated like any other error.
+//
+// Only functions that observe their context.Context can be interrupted by
+// the timeout.
+func WithTaskTimeout(d time.Duration) Configurer {
+	return &taskTimeoutConfigurer{d: d}
+}
+
+type firstSuccessConfigurer struct{}
+
+var _ Configurer = (*firstSuccessConfigurer)(nil)
+
+func (c firstSuccessConfigurer) configure(group *Group) {
+	group.firstSuccess = true
+}
+
+// WithFirstSuccess returns a Configurer that configures a Group, that has
+// also been configured using WithCancel, to cancel itself the first time a
+// function passed to it returns a nil error, rather than the first time one
+// returns a non-nil error. Once a function has succeeded, Group.Wait returns
+// nil, and errors returned by the functions that lost the race are
+// discarded.
+func WithFirstSuccess() Configurer {
+	return &firstSuccessConfigurer{}
+}
+
+// ignores reports whether err matches one of the errors that the Group has
+// been configured to ignore.
+func (g *Group) ignores(err error) bool {
+	for _, target := range g.ignored {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type ignoredErrorsConfigurer struct {
+	errs []error
+}
+
+var _ Configurer = (*ignoredErrorsConfigurer)(nil)
+
+func (c ignoredErrorsConfigurer) configure(group *Group) {
+	group.ignored = append(group.ignored, c.errs...)
+}
+
+// WithIgnoredErrors returns a Configurer that configures a Group to discard
+// any error returned by a function that matches, according to errors.Is, one
+// of errs. An ignored error is neither aggregated into the error returned by
+// Group.Wait nor counts as a failure, so it does not cancel a Group that has
+// also been configured using WithCancel.
+func WithIgnoredErrors(errs ...error) Configurer {
+	return &ignoredErrorsConfigurer{errs: errs}
+}
+
+type retryConfigurer struct {
+	attempts uint
+}
+
+var _ Configurer = (*retryConfigurer)(nil)
+
+func (c retryConfigurer) configure(group *Group) {
+	group.retries = c.attempts
+}
+
+// WithRetry returns a Configurer that configures a Group to call a function
+// passed to it again, up to attempts more times, for as long as it keeps
+// returning a non-nil error. Only the error returned by the final attempt is
+// aggregated. No further attempts are made once the Group has been
+// cancelled.
+func WithRetry(attempts uint) Configurer {
+	return &retryConfigurer{attempts: attempts}
+}
+
+type backoffConfigurer struct {
+	base   time.Duration
+	factor float64
+	max    time.Duration
+}
+
+var _ Configurer = (*backoffConfigurer)(nil)
+
+func (c backoffConfigurer) configure(group *Group) {
+	group.backoff = &c
+}
+
+func (c backoffConfigurer) delay(retry uint) time.Duration {
+	d := float64(c.base) * math.Pow(c.factor, float64(retry))
+	if d > float64(c.max) {
+		return c.max
+	}
+
+	return time.Duration(d)
+}
+
+// WithBackoff returns a Configurer that configures a Group, that has also
+// been configured using WithRetry, to wait before each retry of a function.
+// The wait before the first retry is base, and each subsequent wait is
+// factor times longer than the last, up to max. Waiting is abandoned, along
+// with any remaining retries, if the Group is cancelled.
+//
+// WithRetry decides how many retries are made and WithBackoff decides how
+// long to wait before each of them, so the two can be supplied in either
+// order. Without WithRetry, WithBackoff has no effect.
+func WithBackoff(base time.Duration, factor float64, max time.Duration) Configurer {
+	return &backoffConfigurer{base: base, factor: factor, max: max}
+}
+
+type strictLifecycleConfigurer struct{}
+
+var _ Configurer = (*strictLifecycleConfigurer)(nil)
+
+func (c strictLifecycleConfigurer) configure(group *Group) {
+	group.strict = true
+}
+
+// WithStrictLifecycle returns a Configurer that configures a Group to reject
+// any function passed to it once Group.Wait has been called, returning a
+// WaitedError, since an error returned by such a function would otherwise go
+// unobserved. Group.Reset lifts the restriction.
+func WithStrictLifecycle() Configurer {
+	return &strictLifecycleConfigurer{}
+}
+
+type errorHandlerConfigurer struct {
+	onError func(error)
+}
+
+var _ Configurer = (*errorHandlerConfigurer)(nil)
+
+func (c errorHandlerConfigurer) configure(group *Group) {
+	group.onError = c.onError
+}
+
+// WithErrorHandler returns a Configurer that configures a Group to call
+// onError with each non-nil error returned by a function passed to it, as
+// soon as the function returns and before the error is aggregated. Errors
+// discarded by WithIgnoredErrors are not passed to onError. onError is
+// called from the goroutine that the function ran in, so it must be safe for
+// concurrent use.
+func WithErrorHandler(onError func(error)) Configurer {
+	return &errorHandlerConfigurer{onError: onError}
+}
+
+type errorJoinerConfigurer struct {
+	joiner func(existing, new error) error
+}
+
+var _ Configurer = (*errorJoinerConfigurer)(nil)
+
+func (c errorJoinerConfigurer) configure(group *Group) {
+	group.joiner = c.joiner
+}
+
+// WithErrorJoiner returns a Configurer that configures a Group to aggregate
+// the errors returned by the functions passed to it using joiner rather than
+// multierr.Append. joiner is called with the error aggregated so far, which
+// is nil for the first error, and the error to add to it, and returns the new
+// aggregated error. Calls to joiner are serialised, so it need not be safe
+// for concurrent use.
+//
+// Group.Errors and GroupError.Unwrap only split an aggregated error into the
+// errors it aggregates if it is a *multierr.Error, so with any other joiner
+// they report the aggregated error as a single error.
+func WithErrorJoiner(joiner func(existing, new error) error) Configurer {
+	return &errorJoinerConfigurer{joiner: joiner}
+}
+
+type taskObserverConfigurer struct {
+	onStart  func()
+	onFinish func(err error, d time.Duration)
+}
+
+var _ Configurer = (*taskObserverConfigurer)(nil)
+
+func (c taskObserverConfigurer) configure(group *Group) {
+	group.observer = &c
+}
+
+// WithTaskObserver returns a Configurer that configures a Group to call
+// onStart just before each function passed to it is called, and onFinish
+// once the function has returned, with the error it returned and how long it
+// took. Both are called from the goroutine that the function runs in, so
+// they must be safe for concurrent use.
+func WithTaskObserver(onStart func(), onFinish func(err error, d time.Duration)) Configurer {
+	return &taskObserverConfigurer{onStart: onStart, onFinish: onFinish}
+}
+
+type maxErrorsConfigurer struct {
+	n uint
+}
+
+var _ Configurer = (*maxErrorsConfigurer)(nil)
+
+func (c maxErrorsConfigurer) configure(group *Group) {
+	group.maxErrors = c.n
+}
+
+// WithMaxErrors returns a Configurer that configures a Group, that has also
+// been configured using WithCancel, to tolerate errors until n of them have
+// been aggregated, and only then cancel itself. Functions that are already
+// executing at that point are left to finish, but their errors are
+// discarded, so Group.Wait returns exactly n errors.
+func WithMaxErrors(n uint) Configurer {
+	return &maxErrorsConfigurer{n: n}
+}
+
+type loggerConfigurer struct {
+	logger *slog.Logger
+}
+
+var _ Configurer = (*loggerConfigurer)(nil)
+
+func (c loggerConfigurer) configure(group *Group) {
+	group.logger = c.logger
+}
+
+// WithLogger returns a Configurer that configures a Group to log to logger.
+// Each non-nil error returned by a function passed to the Group is logged at
+// slog.LevelWarn, from the goroutine that the function ran in. The Group
+// being cancelled, and each call to Group.Wait returning, are logged at
+// slog.LevelInfo, the latter alongside the number of functions that have
+// completed and the number that failed.
+func WithLogger(logger *slog.Logger) Configurer {
+	return &loggerConfigurer{logger: logger}
+}
+
+type orderedError struct {
+	index int64
+	err   error
+}
+
+type orderedErrorsConfigurer struct{}
+
+var _ Configurer = (*orderedErrorsConfigurer)(nil)
+
+func (c orderedErrorsConfigurer) configure(group *Group) {
+	group.ordered = true
+}
+
+// WithOrderedErrors returns a Configurer that configures a Group so that the
+// errors aggregated by the error returned by Group.Wait are in the order
+// that the functions which returned them were passed to the Group, rather
+// than the order in which those functions happened to finish. It has no
+// effect on a Group configured using WithFirstError, which still returns the
+// first error to occur.
+func WithOrderedErrors() Configurer {
+	return &orderedErrorsConfigurer{}
+}
+
+type workerPoolConfigurer struct {
+	workers int
+}
+
+var _ Configurer = (*workerPoolConfigurer)(nil)
+
+func (c workerPoolConfigurer) configure(group *Group) {
+	group.pool = newPool(c.workers)
+}
+
+// WithWorkerPool returns a Configurer that configures a Group to execute the
+// functions passed to it on a fixed set of long-lived worker goroutines,
+// rather than launching a new goroutine for each function. Passing a
+// function to the Group blocks until one of the workers is free to take it,
+// so a function must not pass further functions to the Group and then wait
+// for them, or it may deadlock once every worker is doing the same.
+//
+// The workers are shut down once Group.Wait has waited for every function
+// to finish executing, and are started again if more functions are passed
+// to the Group afterwards.
+func WithWorkerPool(workers int) Configurer {
+	return &workerPoolConfigurer{workers: workers}
+}
+
+type jitterer struct {
+	fraction float64
+
+	rngLock sync.Mutex
+	rng     *rand.Rand
+}
+
+func (j *jitterer) apply(d time.Duration) time.Duration {
+	j.rngLock.Lock()
+	defer j.rngLock.Unlock()
+
+	scale := 1 + j.fraction*(2*j.rng.Float64()-1)
+	return time.Duration(max(float64(d)*scale, 0))
+}
+
+type jitterConfigurer struct {
+	fraction float64
+}
+
+var _ Configurer = (*jitterConfigurer)(nil)
+
+func (c jitterConfigurer) configure(group *Group) {
+	group.jitter = &jitterer{
+		fraction: c.fraction,
+		rng:      rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+	}
+}
+
+// WithJitter returns a Configurer that configures a Group, that has also
+// been configured using WithBackoff, to randomly lengthen or shorten each
+// wait before a retry by up to fraction of its duration, so that functions
+// which failed together do not all retry together. Each Group seeds its own
+// source of randomness, so the waits of different groups are uncorrelated.
+// Without WithBackoff, WithJitter has no effect.
+func WithJitter(fraction float64) Configurer {
+	return &jitterConfigurer{fraction: fraction}
+}
+
+type cancelOnErrorConfigurer struct {
+	pred func(error) bool
+}
+
+var _ Configurer = (*cancelOnErrorConfigurer)(nil)
+
+func (c cancelOnErrorConfigurer) configure(group *Group) {
+	group.cancelOn = c.pred
+}
+
+// WithCancelOnError returns a Configurer that configures a Group, that has
+// also been configured using WithCancel, to cancel itself only when a
+// function passed to it returns a non-nil error for which pred returns
+// true. Errors for which pred returns false are aggregated without
+// cancelling the Group. pred is called from the goroutine that ran the
+// function, so it must be safe for concurrent use.
+func WithCancelOnError(pred func(error) bool) Configurer {
+	return &cancelOnErrorConfigurer{pred: pred}
+}
+
+type tracerConfigurer struct {
+	tracer   trace.Tracer
+	spanName string
+}
+
+var _ Configurer = (*tracerConfigurer)(nil)
+
+func (c tracerConfigurer) configure(group *Group) {
+	group.tracer = &c
+}
+
+// WithTracer returns a Configurer that configures a Group to run each
+// function passed to it inside a span named spanName, started using tracer.
+// If the function returns a non-nil error, the error is recorded on the span
+// and the status of the span is set to codes.Error. Spans are started from
+// the context.Context derived by WithCancel if the Group was configured
+// using it, so they nest beneath any span carried by the context.Context
+// passed to WithCancel. Functions launched by Group.GoCtx are passed a
+// context.Context carrying the span, so spans they start nest beneath it.
+func WithTracer(tracer trace.Tracer, spanName string) Configurer {
+	return &tracerConfigurer{tracer: tracer, spanName: spanName}
+}
+
+type adaptiveLimiter struct {
+	min, max uint
+
+	lock      sync.Mutex
+	limit     uint
+	successes uint
+}
+
+// observe adjusts the limit of group after a function passed to it has
+// finished executing, depending on whether it succeeded.
+func (a *adaptiveLimiter) observe(group *Group, succeeded bool) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	limit := a.limit
+	if succeeded {
+		a.successes++
+		if a.successes >= a.limit {
+			a.successes = 0
+			limit = min(a.limit+1, a.max)
+		}
+	} else {
+		a.successes = 0
+		limit = max(a.limit/2, a.min)
+	}
+
+	if limit != a.limit {
+		a.limit = limit
+		group.SetLimit(limit)
+	}
+}
+
+type adaptiveLimitConfigurer struct {
+	min, max uint
+}
+
+var _ Configurer = (*adaptiveLimitConfigurer)(nil)
+
+func (c adaptiveLimitConfigurer) configure(group *Group) {
+	var (
+		lower = max(c.min, 1)
+		upper = max(c.max, lower)
+	)
+	group.adaptive = &adaptiveLimiter{min: lower, max: upper, limit: lower}
+	group.semaphore.Store(newSemaphore(int64(lower)))
+}
+
+// WithAdaptiveLimit returns a Configurer that configures a Group to adjust
+// its limit as functions passed to it finish executing, between min and
+// max, in the style of additive-increase/multiplicative-decrease. The limit
+// starts at min. Each time as many functions as the current limit have
+// succeeded in a row, the limit is increased by one, and each time a
+// function fails, the limit is halved. A min of zero is treated as one.
+// Errors discarded by WithIgnoredErrors count as neither. The current limit
+// is reported by Group.Stats.
+func WithAdaptiveLimit(min, max uint) Configurer {
+	return &adaptiveLimitConfigurer{min: min, max: max}
+}
+
+type deadlockTimeoutConfigurer struct {
+	d time.Duration
+}
+
+var _ Configurer = (*deadlockTimeoutConfigurer)(nil)
+
+func (c deadlockTimeoutConfigurer) configure(group *Group) {
+	group.deadlock = c.d
+}
+
+// WithDeadlockTimeout returns a Configurer that configures a Group so that,
+// if Group.Wait sees none of the goroutines managed by the Group finish
+// executing for d, it gives up and returns a DeadlockError rather than
+// blocking forever. The goroutines are left running, and the Group can be
+// waited on again.
+//
+// A Group whose functions legitimately take longer than d is
+// indistinguishable from one that has deadlocked, so WithDeadlockTimeout is
+// intended as a safety net during development rather than for production
+// use.
+func WithDeadlockTimeout(d time.Duration) Configurer {
+	return &deadlockTimeoutConfigurer{d: d}
 }
@@ -2,14 +2,25 @@ package errgroup_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/jordanhasgul/errgroup"
 	"github.com/jordanhasgul/multierr"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestGroup_Go(t *testing.T) {
@@ -104,70 +115,4114 @@ func TestGroup_Go(t *testing.T) {
 		err := eg.Wait()
 		require.NoError(t, err)
 	})
+
+	t.Run("with error transform", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var (
+			errDropped = errors.New("dropped error")
+			errWrapped = errors.New("wrapped error")
+
+			eg = errgroup.New(
+				errgroup.WithErrorTransform(func(err error) error {
+					if errors.Is(err, errDropped) {
+						return nil
+					}
+
+					return fmt.Errorf("%w: %w", errWrapped, err)
+				}),
+			)
+		)
+		for i := range numGoroutines {
+			err := eg.Go(func() error {
+				if i%2 == 0 {
+					return errDropped
+				}
+
+				return fmt.Errorf("error %d", i)
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.Wait()
+		require.Error(t, err)
+		require.ErrorIs(t, err, errWrapped)
+		require.NotErrorIs(t, err, errDropped)
+
+		var e *multierr.Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, numGoroutines/2, e.Len())
+		require.Equal(t, int64(numGoroutines), eg.Stats().Failed)
+	})
+
+	t.Run("with error transform and first success", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			errDropped = errors.New("dropped error")
+			errTask    = errors.New("task error")
+
+			ctx     = context.Background()
+			cctx, c = errgroup.WithCancel(ctx)
+			eg      = errgroup.New(
+				c,
+				errgroup.WithFirstSuccess(),
+				errgroup.WithErrorTransform(func(err error) error {
+					if errors.Is(err, errDropped) {
+						return nil
+					}
+
+					return err
+				}),
+			)
+			barrier = make(chan struct{})
+		)
+		err := eg.Go(func() error {
+			defer close(barrier)
+			return errDropped
+		})
+		require.NoError(t, err)
+
+		err = eg.Go(func() error {
+			_ = <-barrier
+			time.Sleep(10 * time.Millisecond)
+			if cctx.Err() != nil {
+				return nil
+			}
+
+			return errTask
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.ErrorIs(t, err, errTask)
+		require.NotErrorIs(t, err, errDropped)
+	})
+
+	t.Run("with fault injection", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var (
+			errInjected = errors.New("injected error")
+
+			eg = errgroup.New(
+				errgroup.WithFaultInjection(1, errInjected),
+			)
+			called atomic.Bool
+		)
+		for range numGoroutines {
+			err := eg.Go(func() error {
+				called.Store(true)
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.Wait()
+		require.ErrorIs(t, err, errInjected)
+		require.False(t, called.Load())
+
+		var e *multierr.Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, numGoroutines, e.Len())
+	})
+
+	t.Run("with seeded fault injection", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			numGoroutines = 1 << 6
+			seed          = 42
+		)
+
+		var (
+			errInjected = errors.New("injected error")
+
+			faults = func(seed uint64) []bool {
+				var (
+					eg = errgroup.New(
+						errgroup.WithLimit(1),
+						errgroup.WithSeededFaultInjection(0.5, errInjected, seed),
+					)
+					called = make([]bool, numGoroutines)
+				)
+				for i := range numGoroutines {
+					err := eg.Go(func() error {
+						called[i] = true
+						return nil
+					})
+					require.NoError(t, err)
+				}
+
+				err := eg.Wait()
+				require.ErrorIs(t, err, errInjected)
+				return called
+			}
+		)
+		pattern := faults(seed)
+		require.Contains(t, pattern, true)
+		require.Contains(t, pattern, false)
+		require.Equal(t, pattern, faults(seed))
+		require.NotEqual(t, pattern, faults(seed+1))
+	})
+
+	t.Run("with spin before block", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			maxGoroutines = 1 << 2
+			numGoroutines = 1 << 8
+		)
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithLimit(maxGoroutines),
+				errgroup.WithSpinBeforeBlock(1<<4),
+			)
+			active atomic.Int32
+		)
+		for range numGoroutines {
+			err := eg.Go(func() error {
+				n := active.Add(1)
+				defer active.Add(-1)
+				if n > maxGoroutines {
+					return fmt.Errorf("too many goroutines - got: %d, want: %d", n, maxGoroutines)
+				}
+
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.Wait()
+		require.NoError(t, err)
+	})
+
+	t.Run("with close on cancel", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx   = context.Background()
+			_, cc = errgroup.WithCancel(ctx)
+
+			closed []int
+			eg     = errgroup.New(
+				cc,
+				errgroup.WithCloseOnCancel(closerFunc(func() error {
+					closed = append(closed, 1)
+					return nil
+				})),
+				errgroup.WithCloseOnCancel(closerFunc(func() error {
+					closed = append(closed, 2)
+					return nil
+				})),
+			)
+		)
+		err := eg.Go(func() error {
+			return errors.New("error")
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.Error(t, err)
+		require.Equal(t, []int{2, 1}, closed)
+	})
+
+	t.Run("with limit per cpu", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 8
+
+		var (
+			maxGoroutines = int32(runtime.NumCPU())
+
+			eg = errgroup.New(
+				errgroup.WithLimitPerCPU(1),
+			)
+			active atomic.Int32
+		)
+		for range numGoroutines {
+			err := eg.Go(func() error {
+				n := active.Add(1)
+				defer active.Add(-1)
+				if n > maxGoroutines {
+					return fmt.Errorf("too many goroutines - got: %d, want: %d", n, maxGoroutines)
+				}
+
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.Wait()
+		require.NoError(t, err)
+	})
+
+	t.Run("with default limit", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 8
+
+		var (
+			maxGoroutines = int32(runtime.GOMAXPROCS(0))
+
+			eg = errgroup.New(
+				errgroup.WithDefaultLimit(),
+			)
+			active atomic.Int32
+		)
+		for range numGoroutines {
+			err := eg.Go(func() error {
+				n := active.Add(1)
+				defer active.Add(-1)
+				if n > maxGoroutines {
+					return fmt.Errorf("too many goroutines - got: %d, want: %d", n, maxGoroutines)
+				}
+
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.Wait()
+		require.NoError(t, err)
+	})
+
+	t.Run("with warmup", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			errWarmup = errors.New("warmup error")
+
+			eg = errgroup.New(
+				errgroup.WithWarmup(func() error {
+					return errWarmup
+				}),
+			)
+		)
+		err := eg.Go(func() error {
+			return nil
+		})
+		require.ErrorIs(t, err, errWarmup)
+
+		err = eg.Wait()
+		require.ErrorIs(t, err, errWarmup)
+	})
+
+	t.Run("with serial", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 8
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithSerial(),
+			)
+			active atomic.Int32
+			order  []int
+		)
+		for i := range numGoroutines {
+			err := eg.Go(func() error {
+				n := active.Add(1)
+				defer active.Add(-1)
+				if n > 1 {
+					return fmt.Errorf("too many goroutines - got: %d, want: %d", n, 1)
+				}
+
+				order = append(order, i)
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.Wait()
+		require.NoError(t, err)
+		require.Len(t, order, numGoroutines)
+		for i, n := range order {
+			require.Equal(t, i, n)
+		}
+	})
+
+	t.Run("with cancel debounce", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx      = context.Background()
+			cctx, cc = errgroup.WithCancel(ctx)
+			eg       = errgroup.New(
+				cc,
+				errgroup.WithCancelDebounce(time.Hour),
+			)
+		)
+		err := eg.Go(func() error {
+			return errors.New("error")
+		})
+		require.NoError(t, err)
+
+		time.Sleep(time.Millisecond)
+
+		err = eg.Go(func() error {
+			return nil
+		})
+		require.NoError(t, err)
+		require.NoError(t, cctx.Err())
+
+		var (
+			dctx, dcc = errgroup.WithCancel(ctx)
+			deg       = errgroup.New(
+				dcc,
+				errgroup.WithCancelDebounce(time.Millisecond),
+			)
+		)
+		err = deg.Go(func() error {
+			return errors.New("error")
+		})
+		require.NoError(t, err)
+
+		<-dctx.Done()
+
+		err = deg.Go(func() error {
+			return nil
+		})
+		var ce *errgroup.CancelError
+		require.ErrorAs(t, err, &ce)
+
+		err = eg.Wait()
+		require.Error(t, err)
+
+		err = deg.Wait()
+		require.Error(t, err)
+	})
+
+	t.Run("with timeout escalation", func(t *testing.T) {
+		t.Parallel()
+
+		const maxBreaches = 1 << 2
+
+		var (
+			ctx      = context.Background()
+			cctx, cc = errgroup.WithCancel(ctx)
+			eg       = errgroup.New(
+				cc,
+				errgroup.WithTimeoutEscalation(time.Millisecond, maxBreaches),
+			)
+		)
+		for range maxBreaches {
+			err := eg.Go(func() error {
+				<-cctx.Done()
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		<-cctx.Done()
+
+		err := eg.Go(func() error {
+			return nil
+		})
+		var ce *errgroup.CancelError
+		require.ErrorAs(t, err, &ce)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+	})
+
+	t.Run("with middleware", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			mw = func(name string) func(func() error) func() error {
+				return func(next func() error) func() error {
+					return func() error {
+						return fmt.Errorf("%s: %w", name, next())
+					}
+				}
+			}
+
+			eg = errgroup.New(
+				errgroup.WithMiddleware(mw("outer"), mw("inner")),
+			)
+		)
+		err := eg.Go(func() error {
+			return errors.New("error")
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.ErrorContains(t, err, "outer: inner: error")
+	})
+
+	t.Run("with error writer", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var (
+			sb strings.Builder
+			eg = errgroup.New(
+				errgroup.WithErrorWriter(&sb),
+			)
+		)
+		for i := range numGoroutines {
+			err := eg.Go(func() error {
+				return fmt.Errorf("error %d", i)
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.Wait()
+		require.Error(t, err)
+
+		lines := strings.Split(strings.TrimSpace(sb.String()), "\n")
+		require.Len(t, lines, numGoroutines)
+		for _, line := range lines {
+			require.Regexp(t, `^error \d+$`, line)
+		}
+	})
+
+	t.Run("with max lifetime", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx      = context.Background()
+			cctx, cc = errgroup.WithCancel(ctx)
+			eg       = errgroup.New(
+				cc,
+				errgroup.WithMaxLifetime(time.Millisecond),
+			)
+		)
+		err := eg.Go(func() error {
+			<-cctx.Done()
+			return nil
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.Error(t, err)
+
+		var lee *errgroup.LifetimeExceededError
+		require.ErrorAs(t, err, &lee)
+	})
+
+	t.Run("with recover", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx      = context.Background()
+			cctx, cc = errgroup.WithCancel(ctx)
+			eg       = errgroup.New(
+				cc,
+				errgroup.WithRecover(),
+			)
+		)
+		err := eg.Go(func() error {
+			panic("panic")
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.Error(t, err)
+		require.Error(t, cctx.Err())
+
+		var pe *errgroup.PanicError
+		require.ErrorAs(t, err, &pe)
+		require.Equal(t, "panic", pe.Value)
+		require.NotEmpty(t, pe.Stack)
+	})
+
+	t.Run("with cancel while blocked", func(t *testing.T) {
+		t.Parallel()
+
+		const maxGoroutines = 1 << 2
+
+		var (
+			ctx   = context.Background()
+			_, cc = errgroup.WithCancel(ctx)
+			eg    = errgroup.New(
+				cc,
+				errgroup.WithLimit(maxGoroutines),
+			)
+			barrier = make(chan struct{})
+			trigger = make(chan struct{})
+		)
+		for range maxGoroutines - 1 {
+			err := eg.Go(func() error {
+				_ = <-barrier
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.Go(func() error {
+			_ = <-trigger
+			return errors.New("error")
+		})
+		require.NoError(t, err)
+
+		errCh := make(chan error)
+		go func() {
+			errCh <- eg.Go(func() error {
+				return nil
+			})
+		}()
+
+		require.Eventually(t, func() bool {
+			return eg.BlockedProducers() == 1
+		}, time.Second, time.Millisecond)
+		close(trigger)
+
+		err = <-errCh
+		var ce *errgroup.CancelError
+		require.ErrorAs(t, err, &ce)
+
+		close(barrier)
+
+		err = eg.Wait()
+		require.Error(t, err)
+	})
+
+	t.Run("with first error", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var (
+			errFirst = errors.New("first error")
+
+			eg = errgroup.New(
+				errgroup.WithFirstError(),
+			)
+		)
+		err := eg.Go(func() error {
+			return errFirst
+		})
+		require.NoError(t, err)
+
+		<-eg.FirstErrorChan()
+
+		for i := range numGoroutines {
+			err := eg.Go(func() error {
+				return fmt.Errorf("error %d", i)
+			})
+			require.NoError(t, err)
+		}
+
+		err = eg.Wait()
+		require.Equal(t, errFirst, err)
+	})
+
+	t.Run("with cancel cause", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			errCause = errors.New("cause")
+
+			ctx      = context.Background()
+			cctx, cc = errgroup.WithCancel(ctx)
+			eg       = errgroup.New(cc)
+		)
+		err := eg.Go(func() error {
+			return errCause
+		})
+		require.NoError(t, err)
+
+		<-cctx.Done()
+		require.ErrorIs(t, cctx.Err(), context.Canceled)
+		require.Equal(t, errCause, context.Cause(cctx))
+
+		err = eg.Wait()
+		require.ErrorIs(t, err, errCause)
+		require.Equal(t, errCause, context.Cause(cctx))
+	})
+
+	t.Run("with first success", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var (
+			ctx   = context.Background()
+			_, cc = errgroup.WithCancel(ctx)
+			eg    = errgroup.New(
+				cc,
+				errgroup.WithFirstSuccess(),
+			)
+		)
+		for range numGoroutines {
+			err := eg.GoCtx(func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.Go(func() error {
+			return errors.New("error")
+		})
+		require.NoError(t, err)
+
+		err = eg.Go(func() error {
+			return nil
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+	})
+
+	t.Run("with ignored errors", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			errIgnored = errors.New("ignored error")
+			errOther   = errors.New("other error")
+
+			released = make(chan struct{}, 1)
+
+			ctx     = context.Background()
+			cctx, c = errgroup.WithCancel(ctx)
+			eg      = errgroup.New(
+				c,
+				errgroup.WithIgnoredErrors(errIgnored, context.Canceled),
+				errgroup.WithOnRelease(func() {
+					released <- struct{}{}
+				}),
+			)
+		)
+		err := eg.Go(func() error {
+			return fmt.Errorf("wrapped: %w", errIgnored)
+		})
+		require.NoError(t, err)
+
+		<-released
+		require.NoError(t, cctx.Err())
+
+		err = eg.Go(func() error {
+			return errOther
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.ErrorIs(t, err, errOther)
+		require.NotErrorIs(t, err, errIgnored)
+	})
+
+	t.Run("with retry", func(t *testing.T) {
+		t.Parallel()
+
+		const attempts = 3
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithRetry(attempts),
+			)
+			flaky, failing atomic.Int32
+		)
+		err := eg.Go(func() error {
+			if flaky.Add(1) < attempts {
+				return errors.New("transient error")
+			}
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+		require.Equal(t, int32(attempts), flaky.Load())
+
+		err = eg.Go(func() error {
+			return fmt.Errorf("attempt %d", failing.Add(1))
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.ErrorContains(t, err, fmt.Sprintf("attempt %d", attempts+1))
+		require.Equal(t, int32(attempts+1), failing.Load())
+	})
+
+	t.Run("with backoff", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			attempts = 3
+			base     = 10 * time.Millisecond
+			max      = 25 * time.Millisecond
+		)
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithRetry(attempts),
+				errgroup.WithBackoff(base, 2, max),
+			)
+			calls atomic.Int32
+		)
+		start := time.Now()
+		err := eg.Go(func() error {
+			if calls.Add(1) <= attempts {
+				return errors.New("transient error")
+			}
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+		require.Equal(t, int32(attempts+1), calls.Load())
+		require.GreaterOrEqual(t, time.Since(start), base+2*base+max)
+	})
+
+	t.Run("with backoff and cancel", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var (
+			errTask = errors.New("task error")
+
+			_, c = errgroup.WithCancel(ctx)
+			eg   = errgroup.New(
+				c,
+				errgroup.WithRetry(1),
+				errgroup.WithBackoff(time.Hour, 1, time.Hour),
+			)
+			calls  atomic.Int32
+			failed = make(chan struct{})
+		)
+		err := eg.Go(func() error {
+			calls.Add(1)
+			close(failed)
+			return errTask
+		})
+		require.NoError(t, err)
+
+		<-failed
+		cancel()
+
+		err = eg.Wait()
+		require.ErrorIs(t, err, errTask)
+		require.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("with strict lifecycle", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithStrictLifecycle(),
+			)
+		)
+		err := eg.Go(func() error {
+			return nil
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+
+		err = eg.Go(func() error {
+			return nil
+		})
+		require.Error(t, err)
+
+		var we *errgroup.WaitedError
+		require.ErrorAs(t, err, &we)
+
+		err = eg.TryGo(func() error {
+			return nil
+		})
+		require.ErrorAs(t, err, &we)
+	})
+
+	t.Run("with error handler", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var (
+			errIgnored = errors.New("ignored error")
+
+			handled atomic.Int32
+			eg      = errgroup.New(
+				errgroup.WithIgnoredErrors(errIgnored),
+				errgroup.WithErrorHandler(func(err error) {
+					handled.Add(1)
+				}),
+			)
+		)
+		for i := range numGoroutines {
+			err := eg.Go(func() error {
+				if i%2 == 0 {
+					return errIgnored
+				}
+
+				return errors.New("error")
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.Wait()
+		require.Error(t, err)
+		require.Equal(t, int32(numGoroutines/2), handled.Load())
+	})
+
+	t.Run("with error joiner", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var (
+			errTask = errors.New("task error")
+
+			eg = errgroup.New(
+				errgroup.WithErrorJoiner(func(existing, new error) error {
+					if errors.Is(existing, new) {
+						return existing
+					}
+
+					return errors.Join(existing, new)
+				}),
+			)
+		)
+		for i := range numGoroutines {
+			err := eg.Go(func() error {
+				if i%2 == 0 {
+					return errTask
+				}
+
+				return fmt.Errorf("error %d", i)
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.Wait()
+		require.ErrorIs(t, err, errTask)
+		require.Equal(t, 1, strings.Count(err.Error(), errTask.Error()))
+		require.Equal(t, numGoroutines/2+1, strings.Count(err.Error(), "error"))
+	})
+
+	t.Run("with task observer", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			numGoroutines = 1 << 4
+			sleep         = 5 * time.Millisecond
+		)
+
+		var (
+			errTask = errors.New("task error")
+
+			started, finished, failed, short atomic.Int32
+			eg                               = errgroup.New(
+				errgroup.WithTaskObserver(
+					func() {
+						started.Add(1)
+					},
+					func(err error, d time.Duration) {
+						finished.Add(1)
+						if errors.Is(err, errTask) {
+							failed.Add(1)
+						}
+
+						if d < sleep {
+							short.Add(1)
+						}
+					},
+				),
+			)
+		)
+		for i := range numGoroutines {
+			err := eg.Go(func() error {
+				time.Sleep(sleep)
+				if i%2 == 0 {
+					return errTask
+				}
+
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.Wait()
+		require.ErrorIs(t, err, errTask)
+		require.Equal(t, int32(numGoroutines), started.Load())
+		require.Equal(t, int32(numGoroutines), finished.Load())
+		require.Equal(t, int32(numGoroutines/2), failed.Load())
+		require.Zero(t, short.Load())
+	})
+
+	t.Run("with max errors", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			maxErrors     = 1 << 3
+			numGoroutines = 1 << 6
+		)
+
+		var (
+			ctx     = context.Background()
+			cctx, c = errgroup.WithCancel(ctx)
+			eg      = errgroup.New(
+				c,
+				errgroup.WithMaxErrors(maxErrors),
+			)
+		)
+		for range maxErrors - 1 {
+			err := eg.Go(func() error {
+				return errors.New("error")
+			})
+			require.NoError(t, err)
+		}
+		require.Eventually(t, func() bool {
+			return len(eg.Errors()) == maxErrors-1
+		}, time.Second, time.Millisecond)
+		require.NoError(t, cctx.Err())
+
+		for range numGoroutines {
+			_ = eg.Go(func() error {
+				return errors.New("error")
+			})
+		}
+		<-cctx.Done()
+
+		err := eg.Go(func() error {
+			return nil
+		})
+		require.Error(t, err)
+
+		var ce *errgroup.CancelError
+		require.ErrorAs(t, err, &ce)
+
+		err = eg.Wait()
+		require.Error(t, err)
+		require.Len(t, eg.Errors(), maxErrors)
+	})
+
+	t.Run("with logger", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			buf syncBuffer
+
+			ctx  = context.Background()
+			_, c = errgroup.WithCancel(ctx)
+			eg   = errgroup.New(
+				c,
+				errgroup.WithLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+			)
+		)
+		err := eg.Go(func() error {
+			return errors.New("task error")
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.Error(t, err)
+
+		logs := buf.String()
+		require.Contains(t, logs, `level=WARN msg="errgroup: task failed" error="task error"`)
+		require.Contains(t, logs, `level=INFO msg="errgroup: group cancelled" cause="task error"`)
+		require.Contains(t, logs, `level=INFO msg="errgroup: wait completed" tasks=1 errors=1`)
+	})
+
+	t.Run("with ordered errors", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithOrderedErrors(),
+			)
+		)
+		for i := range numGoroutines {
+			err := eg.Go(func() error {
+				time.Sleep(time.Duration(numGoroutines-i) * time.Millisecond)
+				return fmt.Errorf("error %d", i)
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.Wait()
+		require.Error(t, err)
+
+		var me *multierr.Error
+		require.ErrorAs(t, err, &me)
+
+		errs := me.Unwrap()
+		require.Len(t, errs, numGoroutines)
+		for i, err := range errs {
+			require.EqualError(t, err, fmt.Sprintf("error %d", i))
+		}
+	})
+
+	t.Run("with ordered errors and first error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			errTask = errors.New("task error")
+
+			eg = errgroup.New(
+				errgroup.WithFirstError(),
+				errgroup.WithOrderedErrors(),
+			)
+		)
+		err := eg.Go(func() error {
+			return errTask
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.Equal(t, errTask, err)
+	})
+
+	t.Run("with worker pool", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			numWorkers    = 1 << 2
+			numGoroutines = 1 << 8
+		)
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithWorkerPool(numWorkers),
+			)
+			active atomic.Int32
+			ran    atomic.Int32
+		)
+		for range 2 {
+			for range numGoroutines {
+				err := eg.Go(func() error {
+					n := active.Add(1)
+					defer active.Add(-1)
+					if n > numWorkers {
+						return fmt.Errorf("too many goroutines - got: %d, want: %d", n, numWorkers)
+					}
+
+					ran.Add(1)
+					return nil
+				})
+				require.NoError(t, err)
+			}
+
+			err := eg.Wait()
+			require.NoError(t, err)
+		}
+		require.Equal(t, int32(2*numGoroutines), ran.Load())
+	})
+
+	t.Run("with cancel error cause", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			errTask = errors.New("task error")
+
+			ctx     = context.Background()
+			cctx, c = errgroup.WithCancel(ctx)
+			eg      = errgroup.New(c)
+		)
+		err := eg.Go(func() error {
+			return nil
+		})
+		require.NoError(t, err)
+
+		err = eg.Go(func() error {
+			return errTask
+		})
+		require.NoError(t, err)
+
+		<-cctx.Done()
+
+		err = eg.Go(func() error {
+			return nil
+		})
+		require.ErrorIs(t, err, errTask)
+		require.EqualError(t, err, "group has been cancelled by task 1: task error")
+
+		var ce *errgroup.CancelError
+		require.ErrorAs(t, err, &ce)
+
+		index, ok := ce.TaskIndex()
+		require.True(t, ok)
+		require.Equal(t, 1, index)
+
+		err = eg.Wait()
+		require.ErrorIs(t, err, errTask)
+	})
+
+	t.Run("with jitter", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			attempts = 2
+			base     = 20 * time.Millisecond
+		)
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithRetry(attempts),
+				errgroup.WithBackoff(base, 1, base),
+				errgroup.WithJitter(0.5),
+			)
+			calls atomic.Int32
+		)
+		start := time.Now()
+		err := eg.Go(func() error {
+			if calls.Add(1) <= attempts {
+				return errors.New("transient error")
+			}
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+		require.Equal(t, int32(attempts+1), calls.Load())
+		require.GreaterOrEqual(t, time.Since(start), attempts*base/2)
+	})
+
+	t.Run("with cancel on error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			errFatal       = errors.New("fatal error")
+			errRecoverable = errors.New("recoverable error")
+
+			released = make(chan struct{}, 1)
+
+			ctx     = context.Background()
+			cctx, c = errgroup.WithCancel(ctx)
+			eg      = errgroup.New(
+				c,
+				errgroup.WithCancelOnError(func(err error) bool {
+					return errors.Is(err, errFatal)
+				}),
+				errgroup.WithOnRelease(func() {
+					released <- struct{}{}
+				}),
+			)
+		)
+		err := eg.Go(func() error {
+			return errRecoverable
+		})
+		require.NoError(t, err)
+
+		<-released
+		require.NoError(t, cctx.Err())
+
+		err = eg.Go(func() error {
+			return errFatal
+		})
+		require.NoError(t, err)
+
+		<-released
+		require.ErrorIs(t, context.Cause(cctx), errFatal)
+
+		err = eg.Wait()
+		require.ErrorIs(t, err, errRecoverable)
+		require.ErrorIs(t, err, errFatal)
+	})
+
+	t.Run("with tracer", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var (
+			recorder = tracetest.NewSpanRecorder()
+			provider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+			tracer   = provider.Tracer("errgroup")
+
+			ctx, parent = tracer.Start(context.Background(), "parent")
+			_, c        = errgroup.WithCancel(ctx)
+			eg          = errgroup.New(
+				c,
+				errgroup.WithTracer(tracer, "task"),
+			)
+		)
+		for range numGoroutines {
+			err := eg.Go(func() error {
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.Wait()
+		require.NoError(t, err)
+
+		eg.Reset()
+
+		err = eg.Go(func() error {
+			return errors.New("task error")
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.Error(t, err)
+		parent.End()
+
+		var failed int
+		spans := recorder.Ended()
+		require.Len(t, spans, numGoroutines+2)
+		for _, span := range spans {
+			if span.Name() != "task" {
+				continue
+			}
+
+			require.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+			if span.Status().Code == codes.Error {
+				require.Equal(t, "task error", span.Status().Description)
+				failed++
+			}
+		}
+		require.Equal(t, 1, failed)
+	})
+
+	t.Run("with tracer and context", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			recorder = tracetest.NewSpanRecorder()
+			provider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+			tracer   = provider.Tracer("errgroup")
+
+			eg = errgroup.New(
+				errgroup.WithTracer(tracer, "task"),
+			)
+		)
+		err := eg.GoCtx(func(ctx context.Context) error {
+			_, child := tracer.Start(ctx, "child")
+			child.End()
+			return nil
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 2)
+
+		var task, child sdktrace.ReadOnlySpan
+		for _, span := range spans {
+			switch span.Name() {
+			case "task":
+				task = span
+			case "child":
+				child = span
+			}
+		}
+		require.NotNil(t, task)
+		require.NotNil(t, child)
+		require.Equal(t, task.SpanContext().SpanID(), child.Parent().SpanID())
+	})
+
+	t.Run("with adaptive limit", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithAdaptiveLimit(1, 4),
+			)
+			run = func(err error) {
+				_ = eg.Go(func() error {
+					return err
+				})
+				_ = eg.Wait()
+			}
+		)
+		require.Equal(t, 1, eg.Stats().Limit)
+
+		for _, want := range []int{2, 2, 3, 3, 3, 4, 4, 4, 4, 4} {
+			run(nil)
+			require.Equal(t, want, eg.Stats().Limit)
+		}
+
+		run(errors.New("error"))
+		require.Equal(t, 2, eg.Stats().Limit)
+
+		run(errors.New("error"))
+		require.Equal(t, 1, eg.Stats().Limit)
+
+		run(errors.New("error"))
+		require.Equal(t, 1, eg.Stats().Limit)
+	})
+}
+
+func TestGroup_GoCtx(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		var eg errgroup.Group
+		err := eg.GoCtx(func(ctx context.Context) error {
+			if ctx != context.Background() {
+				return errors.New("unexpected context")
+			}
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+	})
+
+	t.Run("with cancel", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var (
+			ctx   = context.Background()
+			_, cc = errgroup.WithCancel(ctx)
+			eg    = errgroup.New(cc)
+		)
+		for range numGoroutines {
+			err := eg.GoCtx(func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.GoCtx(func(ctx context.Context) error {
+			return errors.New("error")
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.Error(t, err)
+
+		var e *multierr.Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, 1, e.Len())
+		require.EqualError(t, e.Unwrap()[0], "error")
+	})
+
+	t.Run("with task timeout", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		eg := errgroup.New(
+			errgroup.WithTaskTimeout(time.Millisecond),
+		)
+		for range numGoroutines {
+			err := eg.GoCtx(func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.Wait()
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		var e *multierr.Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, numGoroutines, e.Len())
+	})
+
+	t.Run("with cancellation noise", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 6
+
+		var (
+			errTask = errors.New("task error")
+
+			ctx  = context.Background()
+			_, c = errgroup.WithCancel(ctx)
+			eg   = errgroup.New(c)
+		)
+		for range numGoroutines {
+			err := eg.GoCtx(func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.Go(func() error {
+			return errTask
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.ErrorIs(t, err, errTask)
+		require.NotErrorIs(t, err, context.Canceled)
+
+		var me *multierr.Error
+		require.ErrorAs(t, err, &me)
+		require.Equal(t, 1, me.Len())
+	})
+
+	t.Run("with timeout escalation", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx  = context.Background()
+			_, c = errgroup.WithCancel(ctx)
+			eg   = errgroup.New(
+				c,
+				errgroup.WithCancelOnError(func(err error) bool {
+					return false
+				}),
+				errgroup.WithTimeoutEscalation(10*time.Millisecond, 2),
+			)
+		)
+		err := eg.GoCtx(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.False(t, eg.Cancelled())
+	})
+}
+
+func TestGroup_TryGo(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var eg errgroup.Group
+		for i := range numGoroutines {
+			err := eg.TryGo(func() error {
+				return fmt.Errorf("error %d", i)
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.Wait()
+		require.Error(t, err)
+
+		var e *multierr.Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, numGoroutines, e.Len())
+	})
+
+	t.Run("with cancel", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var (
+			ctx   = context.Background()
+			_, cc = errgroup.WithCancel(ctx)
+			eg    = errgroup.New(cc)
+
+			barrier = make(chan struct{})
+		)
+		for i := range numGoroutines {
+			err := eg.Go(func() error {
+				barrier <- struct{}{}
+				return fmt.Errorf("error %d", i)
+			})
+			require.NoError(t, err)
+		}
+
+		for range numGoroutines {
+			_ = <-barrier
+		}
+
+		err := eg.Wait()
+		require.Error(t, err)
+
+		var e *multierr.Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, 1, e.Len())
+
+		err = eg.Go(func() error {
+			return errors.New("another error")
+		})
+		require.Error(t, err)
+
+		var ce *errgroup.CancelError
+		require.ErrorAs(t, err, &ce)
+	})
+
+	t.Run("with limit", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			maxGoroutines = 1 << 4
+			numGoroutines = 1 << 8
+		)
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithLimit(maxGoroutines),
+			)
+			barrier = make(chan struct{}, maxGoroutines)
+		)
+		for i := range maxGoroutines {
+			err := eg.TryGo(func() error {
+				_ = <-barrier
+				return fmt.Errorf("error %d", i)
+			})
+			require.NoError(t, err)
+		}
+
+		for i := range numGoroutines - maxGoroutines {
+			err := eg.TryGo(func() error {
+				return fmt.Errorf("error %d", i)
+			})
+			require.Error(t, err)
+
+			var le *errgroup.LimitError
+			require.ErrorAs(t, err, &le)
+			require.EqualError(t, err, fmt.Sprintf("group is at %d/%d goroutines", maxGoroutines, maxGoroutines))
+		}
+
+		for range maxGoroutines {
+			barrier <- struct{}{}
+		}
+
+		err := eg.Wait()
+		require.Error(t, err)
+
+		var e *multierr.Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, maxGoroutines, e.Len())
+	})
+
+	t.Run("with on release", func(t *testing.T) {
+		t.Parallel()
+
+		const maxGoroutines = 1 << 4
+
+		var (
+			released = make(chan struct{}, maxGoroutines)
+			eg       = errgroup.New(
+				errgroup.WithLimit(maxGoroutines),
+				errgroup.WithOnRelease(func() {
+					released <- struct{}{}
+				}),
+			)
+			barrier = make(chan struct{})
+		)
+		for range maxGoroutines {
+			err := eg.TryGo(func() error {
+				_ = <-barrier
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.TryGo(func() error {
+			return nil
+		})
+		var le *errgroup.LimitError
+		require.ErrorAs(t, err, &le)
+
+		barrier <- struct{}{}
+		<-released
+
+		err = eg.TryGo(func() error {
+			return nil
+		})
+		require.NoError(t, err)
+
+		close(barrier)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+	})
+
+	t.Run("with queued waiters", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			eg      = errgroup.New(errgroup.WithLimit(1))
+			barrier = make(chan struct{})
+		)
+		err := eg.TryGo(func() error {
+			_ = <-barrier
+			return nil
+		})
+		require.NoError(t, err)
+
+		errCh := make(chan error)
+		go func() {
+			errCh <- eg.Go(func() error {
+				return nil
+			})
+		}()
+
+		require.Eventually(t, func() bool {
+			err := eg.TryGo(func() error {
+				return nil
+			})
+
+			var le *errgroup.LimitError
+			return errors.As(err, &le) &&
+				err.Error() == "group is at 1/1 goroutines, with 1 already waiting"
+		}, time.Second, time.Millisecond)
+
+		close(barrier)
+
+		err = <-errCh
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+	})
+}
+
+func TestGroup_WaitContext(t *testing.T) {
+	t.Run("completed", func(t *testing.T) {
+		t.Parallel()
+
+		var eg errgroup.Group
+		err := eg.Go(func() error {
+			return errors.New("error")
+		})
+		require.NoError(t, err)
+
+		err = eg.WaitContext(context.Background())
+		require.ErrorContains(t, err, "error")
+
+		var wie *errgroup.WaitInterruptedError
+		require.False(t, errors.As(err, &wie))
+	})
+
+	t.Run("interrupted", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			eg      errgroup.Group
+			barrier = make(chan struct{})
+		)
+		defer close(barrier)
+
+		err := eg.Go(func() error {
+			_ = <-barrier
+			return nil
+		})
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+
+		err = eg.WaitContext(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		var wie *errgroup.WaitInterruptedError
+		require.ErrorAs(t, err, &wie)
+	})
+}
+
+func TestGroup_WaitTyped(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var (
+			errEven = errors.New("even error")
+
+			eg errgroup.Group
+		)
+		for i := range numGoroutines {
+			err := eg.Go(func() error {
+				if i%2 == 0 {
+					return fmt.Errorf("error %d: %w", i, errEven)
+				}
+
+				return fmt.Errorf("error %d", i)
+			})
+			require.NoError(t, err)
+		}
+
+		ge := eg.WaitTyped()
+		require.Error(t, ge)
+		require.Equal(t, numGoroutines, ge.Count())
+		require.True(t, ge.Has(errEven))
+		require.ErrorIs(t, ge, errEven)
+
+		errs := ge.Filter(func(err error) bool {
+			return errors.Is(err, errEven)
+		})
+		require.Len(t, errs, numGoroutines/2)
+	})
+
+	t.Run("without errors", func(t *testing.T) {
+		t.Parallel()
+
+		var eg errgroup.Group
+		err := eg.Go(func() error {
+			return nil
+		})
+		require.NoError(t, err)
+
+		ge := eg.WaitTyped()
+		require.Nil(t, ge)
+	})
+}
+
+func TestGroup_GoOnce(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var (
+			eg    errgroup.Group
+			calls atomic.Int32
+		)
+		for i := range numGoroutines {
+			key := fmt.Sprintf("key %d", i%2)
+			err := eg.GoOnce(key, func() error {
+				calls.Add(1)
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.Wait()
+		require.NoError(t, err)
+		require.Equal(t, int32(2), calls.Load())
+	})
+}
+
+func TestGroup_Submit(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			errTask = errors.New("task error")
+
+			eg      errgroup.Group
+			barrier = make(chan struct{})
+		)
+		first, err := eg.Submit(func() error {
+			_ = <-barrier
+			return errTask
+		})
+		require.NoError(t, err)
+
+		second, err := eg.Submit(func() error {
+			err := first.Wait()
+			if !errors.Is(err, errTask) {
+				return fmt.Errorf("unexpected error - got: %v", err)
+			}
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		close(barrier)
+
+		require.NoError(t, second.Wait())
+		require.ErrorIs(t, first.Wait(), errTask)
+
+		err = eg.Wait()
+		require.ErrorIs(t, err, errTask)
+
+		var me *multierr.Error
+		require.ErrorAs(t, err, &me)
+		require.Equal(t, 1, me.Len())
+	})
+
+	t.Run("with cancel", func(t *testing.T) {
+		t.Parallel()
+
+		var eg errgroup.Group
+		eg.Cancel()
+
+		future, err := eg.Submit(func() error {
+			return nil
+		})
+		require.Nil(t, future)
+
+		var ce *errgroup.CancelError
+		require.ErrorAs(t, err, &ce)
+	})
+
+	t.Run("with error transform", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			errDropped = errors.New("dropped error")
+
+			eg = errgroup.New(
+				errgroup.WithErrorTransform(func(err error) error {
+					return nil
+				}),
+			)
+		)
+		future, err := eg.Submit(func() error {
+			return errDropped
+		})
+		require.NoError(t, err)
+		require.ErrorIs(t, future.Wait(), errDropped)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+		require.Equal(t, int64(1), eg.Stats().Failed)
+	})
+}
+
+func TestGroup_GoLabeled(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			errTask = errors.New("task error")
+
+			buf     syncBuffer
+			handled = make(chan error, 1)
+			eg      = errgroup.New(
+				errgroup.WithErrorHandler(func(err error) {
+					handled <- err
+				}),
+				errgroup.WithLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+			)
+		)
+		err := eg.GoLabeled("fetch", func() error {
+			return errTask
+		})
+		require.NoError(t, err)
+
+		err = eg.GoLabeled("store", func() error {
+			return nil
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.ErrorIs(t, err, errTask)
+		require.ErrorContains(t, err, "fetch: task error")
+		require.NotContains(t, err.Error(), "store")
+
+		err = <-handled
+		require.EqualError(t, err, "fetch: task error")
+		require.Equal(t, errTask, errors.Unwrap(err))
+
+		require.Contains(t, buf.String(), `error="fetch: task error" label=fetch`)
+	})
+}
+
+func TestGroup_GoN(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 6
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithLimit(1 << 2),
+			)
+			seen = make([]atomic.Bool, numGoroutines)
+		)
+		err := eg.GoN(numGoroutines, func(i int) error {
+			if seen[i].Swap(true) {
+				return fmt.Errorf("index %d seen twice", i)
+			}
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+		for i := range seen {
+			require.True(t, seen[i].Load())
+		}
+	})
+
+	t.Run("with limit", func(t *testing.T) {
+		t.Parallel()
+
+		const maxGoroutines = 1 << 2
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithLimit(maxGoroutines),
+			)
+			barrier  = make(chan struct{})
+			launched atomic.Int32
+		)
+		err := eg.TryGoN(maxGoroutines+1, func(i int) error {
+			launched.Add(1)
+			_ = <-barrier
+			return nil
+		})
+		require.Error(t, err)
+
+		var le *errgroup.LimitError
+		require.ErrorAs(t, err, &le)
+
+		close(barrier)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+		require.Equal(t, int32(maxGoroutines), launched.Load())
+	})
+}
+
+func TestGroup_GoUnlessBusy(t *testing.T) {
+	t.Run("with limit", func(t *testing.T) {
+		t.Parallel()
+
+		const maxGoroutines = 1 << 4
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithLimit(maxGoroutines),
+			)
+			barrier = make(chan struct{})
+		)
+		for range maxGoroutines {
+			ok, err := eg.GoUnlessBusy(func() error {
+				_ = <-barrier
+				return nil
+			})
+			require.NoError(t, err)
+			require.True(t, ok)
+		}
+
+		ok, err := eg.GoUnlessBusy(func() error {
+			return nil
+		})
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		close(barrier)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+	})
+}
+
+func TestGroup_GoWithStop(t *testing.T) {
+	t.Run("with limit", func(t *testing.T) {
+		t.Parallel()
+
+		const maxGoroutines = 1 << 4
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithLimit(maxGoroutines),
+			)
+			barrier = make(chan struct{})
+			stop    = make(chan struct{})
+		)
+		for range maxGoroutines {
+			err := eg.GoWithStop(stop, func() error {
+				_ = <-barrier
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		close(stop)
+
+		err := eg.GoWithStop(stop, func() error {
+			return nil
+		})
+		require.Error(t, err)
+
+		var se *errgroup.StoppedError
+		require.ErrorAs(t, err, &se)
+
+		close(barrier)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+	})
+
+	t.Run("with group cancel", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithLimit(1),
+			)
+			barrier = make(chan struct{})
+			stop    = make(chan struct{})
+			errs    = make(chan error)
+		)
+		err := eg.Go(func() error {
+			_ = <-barrier
+			return nil
+		})
+		require.NoError(t, err)
+
+		go func() {
+			errs <- eg.GoWithStop(stop, func() error {
+				return nil
+			})
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		eg.Cancel()
+
+		var ce *errgroup.CancelError
+		require.ErrorAs(t, <-errs, &ce)
+
+		close(barrier)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+	})
+}
+
+func TestGroup_GoWeighted(t *testing.T) {
+	t.Run("with weighted limit", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			totalWeight   = 10
+			taskWeight    = 3
+			numGoroutines = 1 << 6
+		)
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithWeightedLimit(totalWeight),
+			)
+			inUse atomic.Int64
+		)
+		for range numGoroutines {
+			err := eg.GoWeighted(taskWeight, func() error {
+				weight := inUse.Add(taskWeight)
+				defer inUse.Add(-taskWeight)
+				if weight > totalWeight {
+					return fmt.Errorf("too much weight in use - got: %d, want: %d", weight, totalWeight)
+				}
+
+				time.Sleep(time.Millisecond)
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.Wait()
+		require.NoError(t, err)
+	})
+
+	t.Run("with try", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithWeightedLimit(10),
+			)
+			barrier = make(chan struct{})
+		)
+		err := eg.TryGoWeighted(7, func() error {
+			_ = <-barrier
+			return nil
+		})
+		require.NoError(t, err)
+
+		err = eg.TryGoWeighted(4, func() error {
+			return nil
+		})
+		require.Error(t, err)
+
+		var le *errgroup.LimitError
+		require.ErrorAs(t, err, &le)
+		require.EqualError(t, err, "group has only 3 of its weighted limit of 10 available")
+
+		err = eg.TryGoWeighted(11, func() error {
+			return nil
+		})
+
+		var we *errgroup.WeightError
+		require.ErrorAs(t, err, &we)
+
+		close(barrier)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+	})
+
+	t.Run("with excessive weight", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithWeightedLimit(10),
+			)
+		)
+		err := eg.GoWeighted(11, func() error {
+			return nil
+		})
+		require.Error(t, err)
+
+		var we *errgroup.WeightError
+		require.ErrorAs(t, err, &we)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+	})
+}
+
+func TestGroup_GoWithContext(t *testing.T) {
+	t.Run("with limit", func(t *testing.T) {
+		t.Parallel()
+
+		const maxGoroutines = 1 << 4
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithLimit(maxGoroutines),
+			)
+			barrier = make(chan struct{})
+		)
+		for range maxGoroutines {
+			err := eg.GoWithContext(context.Background(), func() error {
+				_ = <-barrier
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		err := eg.GoWithContext(ctx, func() error {
+			return nil
+		})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		close(barrier)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+	})
+
+	t.Run("with cancel", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx     = context.Background()
+			cctx, c = errgroup.WithCancel(ctx)
+			eg      = errgroup.New(
+				c,
+				errgroup.WithLimit(1),
+			)
+			barrier = make(chan struct{})
+		)
+		err := eg.Go(func() error {
+			_ = <-barrier
+			return nil
+		})
+		require.NoError(t, err)
+
+		go func() {
+			for eg.BlockedProducers() == 0 {
+				time.Sleep(time.Millisecond)
+			}
+			eg.Cancel()
+		}()
+
+		err = eg.GoWithContext(context.Background(), func() error {
+			return nil
+		})
+		require.Error(t, err)
+		require.Error(t, cctx.Err())
+
+		var ce *errgroup.CancelError
+		require.ErrorAs(t, err, &ce)
+
+		close(barrier)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+	})
+
+	t.Run("with group cancel", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithLimit(1),
+			)
+			barrier = make(chan struct{})
+			errs    = make(chan error)
+		)
+		err := eg.Go(func() error {
+			_ = <-barrier
+			return nil
+		})
+		require.NoError(t, err)
+
+		go func() {
+			errs <- eg.GoWithContext(context.Background(), func() error {
+				return nil
+			})
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		eg.Cancel()
+
+		var ce *errgroup.CancelError
+		require.ErrorAs(t, <-errs, &ce)
+
+		close(barrier)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+	})
+}
+
+func TestGroup_GoUrgent(t *testing.T) {
+	t.Run("with urgent overshoot", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			maxGoroutines = 1 << 4
+			maxOvershoot  = 1 << 2
+		)
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithLimit(maxGoroutines),
+				errgroup.WithUrgentOvershoot(maxOvershoot),
+			)
+			barrier = make(chan struct{})
+			active  atomic.Int32
+		)
+		for range maxGoroutines {
+			err := eg.Go(func() error {
+				active.Add(1)
+				_ = <-barrier
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		for range maxOvershoot {
+			err := eg.GoUrgent(func() error {
+				active.Add(1)
+				_ = <-barrier
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		require.Eventually(t, func() bool {
+			return active.Load() == maxGoroutines+maxOvershoot
+		}, time.Second, time.Millisecond)
+
+		close(barrier)
+
+		err := eg.Wait()
+		require.NoError(t, err)
+	})
+
+	t.Run("with group cancel", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithLimit(1),
+			)
+			barrier = make(chan struct{})
+			errs    = make(chan error)
+		)
+		err := eg.Go(func() error {
+			_ = <-barrier
+			return nil
+		})
+		require.NoError(t, err)
+
+		go func() {
+			errs <- eg.GoUrgent(func() error {
+				return nil
+			})
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		eg.Cancel()
+
+		var ce *errgroup.CancelError
+		require.ErrorAs(t, <-errs, &ce)
+
+		close(barrier)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+	})
+}
+
+func TestGoAutoChunk(t *testing.T) {
+	t.Run("with limit", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			maxGoroutines = 1 << 2
+			numItems      = 1<<8 + 1
+		)
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithLimit(maxGoroutines),
+			)
+			chunks atomic.Int32
+			sum    atomic.Int64
+
+			items = make([]int, numItems)
+		)
+		for i := range items {
+			items[i] = i
+		}
+
+		err := errgroup.GoAutoChunk(eg, items, func(chunk []int) error {
+			chunks.Add(1)
+			for _, item := range chunk {
+				sum.Add(int64(item))
+			}
+
+			return nil
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+		require.Equal(t, int32(maxGoroutines), chunks.Load())
+		require.Equal(t, int64(numItems*(numItems-1)/2), sum.Load())
+	})
+
+	t.Run("with zero limit", func(t *testing.T) {
+		t.Parallel()
+
+		const numItems = 1 << 8
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithLimit(0),
+			)
+			chunks   atomic.Int32
+			sum      atomic.Int64
+			launched = make(chan error)
+
+			items = make([]int, numItems)
+		)
+		for i := range items {
+			items[i] = i
+		}
+
+		go func() {
+			launched <- errgroup.GoAutoChunk(eg, items, func(chunk []int) error {
+				chunks.Add(1)
+				for _, item := range chunk {
+					sum.Add(int64(item))
+				}
+
+				return nil
+			})
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		eg.SetLimit(1)
+		require.NoError(t, <-launched)
+
+		err := eg.Wait()
+		require.NoError(t, err)
+		require.Equal(t, int32(1), chunks.Load())
+		require.Equal(t, int64(numItems*(numItems-1)/2), sum.Load())
+	})
+}
+
+func TestWithContext(t *testing.T) {
+	t.Run("with error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			errTask = errors.New("task error")
+
+			eg, ctx = errgroup.WithContext(context.Background())
+		)
+		err := eg.Go(func() error {
+			return errTask
+		})
+		require.NoError(t, err)
+
+		<-ctx.Done()
+		require.ErrorIs(t, context.Cause(ctx), errTask)
+
+		err = eg.Wait()
+		require.ErrorIs(t, err, errTask)
+	})
+
+	t.Run("with wait", func(t *testing.T) {
+		t.Parallel()
+
+		eg, ctx := errgroup.WithContext(context.Background())
+		err := eg.Go(func() error {
+			return nil
+		})
+		require.NoError(t, err)
+		require.NoError(t, ctx.Err())
+
+		err = eg.Wait()
+		require.NoError(t, err)
+		require.Error(t, ctx.Err())
+	})
+}
+
+func TestMap(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		const numItems = 1 << 8
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithLimit(1 << 2),
+			)
+			items = make([]int, numItems)
+		)
+		for i := range items {
+			items[i] = i
+		}
+
+		results, err := errgroup.Map(eg, items, func(item int) (string, error) {
+			if item%2 == 0 {
+				return "", fmt.Errorf("error %d", item)
+			}
+
+			return fmt.Sprint(item), nil
+		})
+		require.Error(t, err)
+
+		var e *multierr.Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, numItems/2, e.Len())
+
+		require.Len(t, results, numItems)
+		for i, result := range results {
+			if i%2 == 0 {
+				require.Empty(t, result)
+			} else {
+				require.Equal(t, fmt.Sprint(i), result)
+			}
+		}
+	})
+}
+
+func TestRun(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var (
+			ran atomic.Int32
+			fns = make([]func() error, numGoroutines)
+		)
+		for i := range fns {
+			fns[i] = func() error {
+				ran.Add(1)
+				if i%2 == 0 {
+					return fmt.Errorf("error %d", i)
+				}
+
+				return nil
+			}
+		}
+
+		err := errgroup.Run(nil, fns...)
+		require.Error(t, err)
+		require.Equal(t, int32(numGoroutines), ran.Load())
+
+		var e *multierr.Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, numGoroutines/2, e.Len())
+	})
+
+	t.Run("with limit", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			maxGoroutines = 1 << 2
+			numGoroutines = 1 << 6
+		)
+
+		var (
+			active atomic.Int32
+			fns    = make([]func() error, numGoroutines)
+		)
+		for i := range fns {
+			fns[i] = func() error {
+				n := active.Add(1)
+				defer active.Add(-1)
+				if n > maxGoroutines {
+					return fmt.Errorf("too many goroutines - got: %d, want: %d", n, maxGoroutines)
+				}
+
+				return nil
+			}
+		}
+
+		err := errgroup.Run(
+			[]errgroup.Configurer{errgroup.WithLimit(maxGoroutines)},
+			fns...,
+		)
+		require.NoError(t, err)
+	})
+}
+
+func TestForEach(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		const numItems = 1 << 8
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithLimit(1 << 2),
+			)
+			items = make([]int, numItems)
+			seen  = make([]atomic.Bool, numItems)
+		)
+		for i := range items {
+			items[i] = i * i
+		}
+
+		err := errgroup.ForEach(eg, items, func(i int, item int) error {
+			if item != i*i {
+				return fmt.Errorf("item %d at index %d", item, i)
+			}
+
+			seen[i].Store(true)
+			return nil
+		})
+		require.NoError(t, err)
+		for i := range seen {
+			require.True(t, seen[i].Load())
+		}
+	})
+
+	t.Run("with cancel", func(t *testing.T) {
+		t.Parallel()
+
+		const numItems = 1 << 8
+
+		var (
+			errItem = errors.New("item error")
+
+			ctx  = context.Background()
+			_, c = errgroup.WithCancel(ctx)
+			eg   = errgroup.New(
+				c,
+				errgroup.WithLimit(1),
+			)
+			launched atomic.Int32
+		)
+		err := errgroup.ForEach(eg, make([]int, numItems), func(i int, item int) error {
+			launched.Add(1)
+			if i == 0 {
+				return errItem
+			}
+
+			return nil
+		})
+		require.ErrorIs(t, err, errItem)
+
+		var ce *errgroup.CancelError
+		require.ErrorAs(t, err, &ce)
+		require.Less(t, launched.Load(), int32(numItems))
+	})
+}
+
+func TestRunFailFastResults(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		const numFuncs = 1 << 4
+
+		fs := make([]func(context.Context) (int, error), numFuncs)
+		for i := range fs {
+			fs[i] = func(context.Context) (int, error) {
+				return i, nil
+			}
+		}
+
+		results, err := errgroup.RunFailFastResults(context.Background(), fs...)
+		require.NoError(t, err)
+		for i, result := range results {
+			require.Equal(t, i, result)
+		}
+	})
+
+	t.Run("with error", func(t *testing.T) {
+		t.Parallel()
+
+		const numFuncs = 1 << 4
+
+		var (
+			errFirst = errors.New("first error")
+
+			fs = make([]func(context.Context) (int, error), numFuncs)
+		)
+		fs[0] = func(context.Context) (int, error) {
+			return 0, errFirst
+		}
+		for i := 1; i < numFuncs; i++ {
+			fs[i] = func(ctx context.Context) (int, error) {
+				<-ctx.Done()
+				return 0, ctx.Err()
+			}
+		}
+
+		results, err := errgroup.RunFailFastResults(context.Background(), fs...)
+		require.ErrorIs(t, err, errFirst)
+		require.Len(t, results, numFuncs)
+	})
+
+	t.Run("with late success", func(t *testing.T) {
+		t.Parallel()
+
+		errFirst := errors.New("first error")
+
+		results, err := errgroup.RunFailFastResults(
+			context.Background(),
+			func(context.Context) (int, error) {
+				return 0, errFirst
+			},
+			func(ctx context.Context) (int, error) {
+				<-ctx.Done()
+				return 7, nil
+			},
+		)
+		require.ErrorIs(t, err, errFirst)
+		require.Equal(t, []int{0, 0}, results)
+	})
+}
+
+func TestGroup_SetLimit(t *testing.T) {
+	t.Run("grow", func(t *testing.T) {
+		t.Parallel()
+
+		const maxGoroutines = 1 << 2
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithLimit(maxGoroutines),
+			)
+			barrier = make(chan struct{})
+			f       = func() error {
+				_ = <-barrier
+				return nil
+			}
+		)
+		for range maxGoroutines {
+			err := eg.TryGo(f)
+			require.NoError(t, err)
+		}
+
+		err := eg.TryGo(f)
+		var le *errgroup.LimitError
+		require.ErrorAs(t, err, &le)
+
+		eg.SetLimit(2 * maxGoroutines)
+		for range maxGoroutines {
+			err := eg.TryGo(f)
+			require.NoError(t, err)
+		}
+
+		err = eg.TryGo(f)
+		require.ErrorAs(t, err, &le)
+
+		close(barrier)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+	})
+
+	t.Run("shrink", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			maxGoroutines = 1 << 2
+			numGoroutines = 1 << 6
+		)
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithLimit(2 * maxGoroutines),
+			)
+			barrier = make(chan struct{})
+			active  atomic.Int32
+		)
+		for range 2 * maxGoroutines {
+			err := eg.Go(func() error {
+				_ = <-barrier
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		eg.SetLimit(maxGoroutines)
+		close(barrier)
+
+		for range numGoroutines {
+			err := eg.Go(func() error {
+				n := active.Add(1)
+				defer active.Add(-1)
+				if n > maxGoroutines {
+					return fmt.Errorf("too many goroutines - got: %d, want: %d", n, maxGoroutines)
+				}
+
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.Wait()
+		require.NoError(t, err)
+	})
+}
+
+func TestGroup_GoFinal(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var (
+			eg        errgroup.Group
+			completed atomic.Int32
+		)
+		for range numGoroutines {
+			eg.GoFinal(func() error {
+				n := completed.Load()
+				if n != numGoroutines {
+					return fmt.Errorf("final ran too early - got: %d, want: %d", n, numGoroutines)
+				}
+
+				return errors.New("final error")
+			})
+		}
+
+		for range numGoroutines {
+			err := eg.Go(func() error {
+				time.Sleep(time.Millisecond)
+				completed.Add(1)
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.Wait()
+		require.Error(t, err)
+
+		var e *multierr.Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, numGoroutines, e.Len())
+		for _, err := range e.Unwrap() {
+			require.EqualError(t, err, "final error")
+		}
+	})
+}
+
+func TestGroup_Errors(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			errFirst  = errors.New("first error")
+			errSecond = errors.New("second error")
+
+			released = make(chan struct{}, 1)
+			eg       = errgroup.New(
+				errgroup.WithOnRelease(func() {
+					released <- struct{}{}
+				}),
+			)
+			barrier = make(chan struct{})
+		)
+		require.Empty(t, eg.Errors())
+
+		err := eg.Go(func() error {
+			return errFirst
+		})
+		require.NoError(t, err)
+		<-released
+
+		err = eg.Go(func() error {
+			_ = <-barrier
+			return errSecond
+		})
+		require.NoError(t, err)
+
+		errs := eg.Errors()
+		require.Len(t, errs, 1)
+		require.ErrorIs(t, errs[0], errFirst)
+
+		close(barrier)
+		<-released
+
+		err = eg.Wait()
+		require.Error(t, err)
+		require.Len(t, eg.Errors(), 2)
+	})
+}
+
+func TestGroup_FlushErrors(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			errFirst  = errors.New("first error")
+			errSecond = errors.New("second error")
+
+			eg errgroup.Group
+		)
+		require.NoError(t, eg.FlushErrors())
+
+		err := eg.Go(func() error {
+			return errFirst
+		})
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			return len(eg.Errors()) == 1
+		}, time.Second, time.Millisecond)
+
+		err = eg.FlushErrors()
+		require.ErrorIs(t, err, errFirst)
+		require.Empty(t, eg.Errors())
+
+		err = eg.Go(func() error {
+			return errSecond
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.ErrorIs(t, err, errSecond)
+		require.NotErrorIs(t, err, errFirst)
+	})
+}
+
+func TestGroup_TryWait(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			errTask = errors.New("task error")
+
+			eg      errgroup.Group
+			barrier = make(chan struct{})
+		)
+		err := eg.Go(func() error {
+			_ = <-barrier
+			return errTask
+		})
+		require.NoError(t, err)
+
+		err, ok := eg.TryWait()
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		close(barrier)
+
+		require.Eventually(t, func() bool {
+			_, ok := eg.TryWait()
+			return ok
+		}, time.Second, time.Millisecond)
+
+		err, ok = eg.TryWait()
+		require.ErrorIs(t, err, errTask)
+		require.True(t, ok)
+
+		err = eg.Wait()
+		require.ErrorIs(t, err, errTask)
+	})
+
+	t.Run("with later launch", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			errTask = errors.New("task error")
+
+			eg      errgroup.Group
+			barrier = make(chan struct{})
+		)
+		err, ok := eg.TryWait()
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		time.Sleep(10 * time.Millisecond)
+
+		err = eg.Go(func() error {
+			_ = <-barrier
+			return errTask
+		})
+		require.NoError(t, err)
+
+		err, ok = eg.TryWait()
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		close(barrier)
+
+		err = eg.Wait()
+		require.ErrorIs(t, err, errTask)
+
+		err, ok = eg.TryWait()
+		require.ErrorIs(t, err, errTask)
+		require.True(t, ok)
+	})
+}
+
+func TestGroup_Wait(t *testing.T) {
+	t.Run("with wait heartbeat", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var (
+			beats atomic.Int32
+			eg    = errgroup.New(
+				errgroup.WithWaitHeartbeat(time.Millisecond, func(active int) {
+					if active > 0 {
+						beats.Add(1)
+					}
+				}),
+			)
+
+			barrier = make(chan struct{})
+		)
+		for range numGoroutines {
+			err := eg.Go(func() error {
+				_ = <-barrier
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		go func() {
+			for beats.Load() < 2 {
+				time.Sleep(time.Millisecond)
+			}
+			close(barrier)
+		}()
+
+		err := eg.Wait()
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, beats.Load(), int32(2))
+	})
+
+	t.Run("with hook panic handler", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			recovered = make(chan any, 1)
+			eg        = errgroup.New(
+				errgroup.WithWaitHeartbeat(time.Millisecond, func(int) {
+					panic("heartbeat panic")
+				}),
+				errgroup.WithHookPanicHandler(func(v any) {
+					select {
+					case recovered <- v:
+					default:
+					}
+				}),
+			)
+
+			barrier = make(chan struct{})
+		)
+		err := eg.Go(func() error {
+			_ = <-barrier
+			return nil
+		})
+		require.NoError(t, err)
+
+		var v any
+		go func() {
+			v = <-recovered
+			close(barrier)
+		}()
+
+		err = eg.Wait()
+		require.NoError(t, err)
+		require.Equal(t, "heartbeat panic", v)
+	})
+
+	t.Run("with concurrent waiters", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			numGoroutines = 1 << 4
+			numWaiters    = 1 << 4
+		)
+
+		var (
+			ctx     = context.Background()
+			cctx, c = errgroup.WithCancel(ctx)
+			eg      = errgroup.New(c)
+
+			barrier = make(chan struct{})
+			finals  atomic.Int32
+		)
+		for i := range numGoroutines {
+			err := eg.Go(func() error {
+				_ = <-barrier
+				return fmt.Errorf("error %d", i)
+			})
+			require.NoError(t, err)
+		}
+
+		eg.GoFinal(func() error {
+			finals.Add(1)
+			return nil
+		})
+
+		var (
+			wg   sync.WaitGroup
+			errs = make([]error, numWaiters)
+		)
+		for i := range numWaiters {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				errs[i] = eg.Wait()
+				if finals.Load() != 1 {
+					errs[i] = errors.New("returned before final function ran")
+				}
+			}()
+		}
+
+		close(barrier)
+		wg.Wait()
+
+		require.Error(t, errs[0])
+		for _, err := range errs {
+			require.Same(t, errs[0], err)
+		}
+		require.Error(t, cctx.Err())
+	})
+
+	t.Run("with deadlock timeout", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 3
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithLimit(1),
+				errgroup.WithDeadlockTimeout(20*time.Millisecond),
+			)
+			barrier = make(chan struct{})
+		)
+		for range numGoroutines {
+			err := eg.Go(func() error {
+				time.Sleep(5 * time.Millisecond)
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		err := eg.Go(func() error {
+			_ = <-barrier
+			return nil
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.Error(t, err)
+
+		var de *errgroup.DeadlockError
+		require.ErrorAs(t, err, &de)
+		require.EqualError(t, err, "group made no progress for 20ms with 1 goroutines still active")
+
+		close(barrier)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+	})
+}
+
+func TestGroup_FirstErrorChan(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			eg      errgroup.Group
+			errCh   = eg.FirstErrorChan()
+			barrier = make(chan struct{})
+		)
+		err := eg.Go(func() error {
+			return errors.New("first error")
+		})
+		require.NoError(t, err)
+
+		err = <-errCh
+		require.EqualError(t, err, "first error")
+
+		_, ok := <-errCh
+		require.False(t, ok)
+
+		err = eg.Go(func() error {
+			_ = <-barrier
+			return errors.New("second error")
+		})
+		require.NoError(t, err)
+		close(barrier)
+
+		err = eg.Wait()
+		require.Error(t, err)
+		require.Equal(t, errCh, eg.FirstErrorChan())
+	})
+
+	t.Run("after first error", func(t *testing.T) {
+		t.Parallel()
+
+		var eg errgroup.Group
+		err := eg.Go(func() error {
+			return errors.New("first error")
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.Error(t, err)
+
+		err = <-eg.FirstErrorChan()
+		require.EqualError(t, err, "first error")
+	})
+}
+
+func TestWithLeakCheck(t *testing.T) {
+	t.Run("without wait", func(t *testing.T) {
+		var buf syncBuffer
+		log.SetOutput(&buf)
+		defer log.SetOutput(os.Stderr)
+
+		func() {
+			eg := errgroup.New(
+				errgroup.WithLeakCheck(),
+			)
+			_ = eg.Go(func() error {
+				return nil
+			})
+		}()
+
+		require.Eventually(t, func() bool {
+			runtime.GC()
+			return strings.Contains(buf.String(), "without Wait being called")
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestLinkCancellation(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx       = context.Background()
+			ctx1, cc1 = errgroup.WithCancel(ctx)
+			ctx2, cc2 = errgroup.WithCancel(ctx)
+
+			eg1 = errgroup.New(cc1)
+			eg2 = errgroup.New(cc2)
+			eg3 errgroup.Group
+		)
+		errgroup.LinkCancellation(eg1, eg2, &eg3)
+
+		err := eg1.Go(func() error {
+			return errors.New("error")
+		})
+		require.NoError(t, err)
+
+		<-ctx1.Done()
+		<-ctx2.Done()
+
+		err = eg2.Go(func() error {
+			return nil
+		})
+		require.Error(t, err)
+
+		var ce *errgroup.CancelError
+		require.ErrorAs(t, err, &ce)
+
+		err = eg3.Go(func() error {
+			return nil
+		})
+		require.ErrorAs(t, err, &ce)
+
+		err = eg1.Wait()
+		require.Error(t, err)
+
+		err = eg2.Wait()
+		require.NoError(t, err)
+	})
+}
+
+func TestGroup_Clone(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		const maxGoroutines = 1 << 4
+
+		var (
+			ctx      = context.Background()
+			cctx, cc = errgroup.WithCancel(ctx)
+			eg       = errgroup.New(
+				cc,
+				errgroup.WithLimit(maxGoroutines),
+			)
+			clone = eg.Clone()
+
+			barrier = make(chan struct{})
+		)
+		for range maxGoroutines {
+			err := clone.TryGo(func() error {
+				_ = <-barrier
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		err := clone.TryGo(func() error {
+			return nil
+		})
+		var le *errgroup.LimitError
+		require.ErrorAs(t, err, &le)
+
+		err = eg.TryGo(func() error {
+			return errors.New("error")
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.Error(t, err)
+		require.Error(t, cctx.Err())
+
+		close(barrier)
+
+		err = clone.Wait()
+		require.NoError(t, err)
+	})
+
+	t.Run("with shared cancel", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx      = context.Background()
+			cctx, cc = errgroup.WithCancel(ctx)
+			eg       = errgroup.New(cc)
+			other    = errgroup.New(cc)
+		)
+		err := other.Go(func() error {
+			return errors.New("error")
+		})
+		require.NoError(t, err)
+
+		err = other.Wait()
+		require.Error(t, err)
+		require.Error(t, cctx.Err())
+
+		clone := eg.Clone()
+		err = clone.GoCtx(func(ctx context.Context) error {
+			return ctx.Err()
+		})
+		require.NoError(t, err)
+
+		err = clone.Wait()
+		require.NoError(t, err)
+	})
+}
+
+func TestGroup_WaitReport(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		eg := errgroup.New(
+			errgroup.WithLimit(numGoroutines),
+		)
+		for i := range numGoroutines {
+			err := eg.Go(func() error {
+				time.Sleep(time.Millisecond)
+				if i%2 == 0 {
+					return fmt.Errorf("error %d", i)
+				}
+
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		report := eg.WaitReport()
+		require.Error(t, report.Err)
+		require.Equal(t, int64(numGoroutines), report.Submitted)
+		require.Equal(t, int64(numGoroutines/2), report.Succeeded)
+		require.Equal(t, int64(numGoroutines/2), report.Failed)
+		require.Equal(t, int64(0), report.Skipped)
+		require.GreaterOrEqual(t, report.Duration, time.Millisecond)
+		require.LessOrEqual(t, report.MaxConcurrency, numGoroutines)
+	})
+
+	t.Run("with cancel", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx      = context.Background()
+			cctx, cc = errgroup.WithCancel(ctx)
+			eg       = errgroup.New(cc)
+		)
+		err := eg.Go(func() error {
+			return errors.New("error")
+		})
+		require.NoError(t, err)
+
+		<-cctx.Done()
+
+		err = eg.Go(func() error {
+			return nil
+		})
+		require.Error(t, err)
+
+		report := eg.WaitReport()
+		require.Error(t, report.Err)
+		require.Equal(t, int64(1), report.Submitted)
+		require.Equal(t, int64(1), report.Failed)
+		require.Equal(t, int64(1), report.Skipped)
+	})
+}
+
+func TestGroup_Stats(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var (
+			ctx  = context.Background()
+			_, c = errgroup.WithCancel(ctx)
+			eg   = errgroup.New(c)
+
+			barrier = make(chan struct{})
+		)
+		for range numGoroutines {
+			err := eg.Go(func() error {
+				_ = <-barrier
+				return nil
+			})
+			require.NoError(t, err)
+		}
+
+		stats := eg.Stats()
+		require.Equal(t, int64(numGoroutines), stats.Launched)
+		require.Zero(t, stats.Completed)
+		require.Equal(t, numGoroutines, stats.Active)
+		require.False(t, stats.Cancelled)
+
+		err := eg.Go(func() error {
+			return errors.New("error")
+		})
+		require.NoError(t, err)
+
+		close(barrier)
+
+		err = eg.Wait()
+		require.Error(t, err)
+
+		stats = eg.Stats()
+		require.Equal(t, int64(numGoroutines+1), stats.Launched)
+		require.Equal(t, int64(numGoroutines+1), stats.Completed)
+		require.Equal(t, int64(1), stats.Failed)
+		require.Zero(t, stats.Active)
+		require.True(t, stats.Cancelled)
+	})
+}
+
+func TestGroup_Reset(t *testing.T) {
+	t.Run("with cancel", func(t *testing.T) {
+		t.Parallel()
+
+		const maxGoroutines = 1 << 2
+
+		var (
+			ctx   = context.Background()
+			_, cc = errgroup.WithCancel(ctx)
+			eg    = errgroup.New(
+				cc,
+				errgroup.WithLimit(maxGoroutines),
+			)
+		)
+		err := eg.Go(func() error {
+			return errors.New("error")
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.Error(t, err)
+
+		eg.Reset()
+
+		for range maxGoroutines {
+			err := eg.GoCtx(func(ctx context.Context) error {
+				return ctx.Err()
+			})
+			require.NoError(t, err)
+		}
+
+		err = eg.Wait()
+		require.NoError(t, err)
+	})
+
+	t.Run("with counters", func(t *testing.T) {
+		t.Parallel()
+
+		const sleep = 10 * time.Millisecond
+
+		var eg errgroup.Group
+		err := eg.Go(func() error {
+			time.Sleep(sleep)
+			return errors.New("error")
+		})
+		require.NoError(t, err)
+
+		report := eg.WaitReport()
+		require.Error(t, report.Err)
+
+		eg.Reset()
+		require.Equal(t, errgroup.Stats{}, eg.Stats())
+		require.Equal(t, 0, eg.MaxConcurrency())
+
+		err = eg.Go(func() error {
+			return nil
+		})
+		require.NoError(t, err)
+
+		report = eg.WaitReport()
+		require.NoError(t, report.Err)
+		require.Equal(t, int64(1), report.Submitted)
+		require.Equal(t, int64(1), report.Succeeded)
+		require.Equal(t, int64(0), report.Failed)
+		require.Equal(t, int64(0), report.Skipped)
+		require.Equal(t, 1, report.MaxConcurrency)
+		require.Less(t, report.Duration, sleep)
+	})
+
+	t.Run("with max lifetime", func(t *testing.T) {
+		t.Parallel()
+
+		const lifetime = 50 * time.Millisecond
+
+		eg := errgroup.New(
+			errgroup.WithMaxLifetime(lifetime),
+		)
+		err := eg.Wait()
+		require.NoError(t, err)
+
+		time.Sleep(2 * lifetime)
+		eg.Reset()
+
+		err = eg.Go(func() error {
+			time.Sleep(2 * lifetime)
+			return nil
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+
+		var lee *errgroup.LifetimeExceededError
+		require.ErrorAs(t, err, &lee)
+	})
+
+	t.Run("with warmup", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			calls atomic.Int32
+			eg    = errgroup.New(
+				errgroup.WithWarmup(func() error {
+					if calls.Add(1) == 1 {
+						return errors.New("warmup error")
+					}
+
+					return nil
+				}),
+			)
+		)
+		err := eg.Go(func() error {
+			return nil
+		})
+		require.EqualError(t, err, "warmup error")
+
+		err = eg.Wait()
+		require.Error(t, err)
+
+		eg.Reset()
+		require.Equal(t, int32(2), calls.Load())
+
+		err = eg.Go(func() error {
+			return nil
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+	})
+
+	t.Run("with close on cancel", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			closes atomic.Int32
+			eg     = errgroup.New(
+				errgroup.WithCloseOnCancel(closerFunc(func() error {
+					closes.Add(1)
+					return nil
+				})),
+			)
+		)
+		for range 2 {
+			eg.Cancel()
+
+			err := eg.Wait()
+			require.NoError(t, err)
+
+			eg.Reset()
+		}
+
+		require.Equal(t, int32(1), closes.Load())
+	})
+}
+
+func TestGroup_Cancel(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		var eg errgroup.Group
+		eg.Cancel()
+
+		err := eg.Go(func() error {
+			return nil
+		})
+		require.Error(t, err)
+
+		var ce *errgroup.CancelError
+		require.ErrorAs(t, err, &ce)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+	})
+
+	t.Run("with cancel", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx     = context.Background()
+			cctx, c = errgroup.WithCancel(ctx)
+			eg      = errgroup.New(c)
+		)
+		err := eg.GoCtx(func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		require.NoError(t, err)
+		require.False(t, eg.Cancelled())
+
+		eg.Cancel()
+		require.True(t, eg.Cancelled())
+		<-cctx.Done()
+
+		err = eg.TryGo(func() error {
+			return nil
+		})
+		require.Error(t, err)
+
+		var ce *errgroup.CancelError
+		require.ErrorAs(t, err, &ce)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+	})
+
+	t.Run("with limit", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithLimit(1),
+			)
+			barrier = make(chan struct{})
+			called  atomic.Bool
+			errs    = make(chan error)
+		)
+		err := eg.Go(func() error {
+			_ = <-barrier
+			return nil
+		})
+		require.NoError(t, err)
+
+		go func() {
+			errs <- eg.Go(func() error {
+				called.Store(true)
+				return nil
+			})
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		eg.Cancel()
+
+		var ce *errgroup.CancelError
+		require.ErrorAs(t, <-errs, &ce)
+
+		close(barrier)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+		require.False(t, called.Load())
+	})
+}
+
+func TestGroup_Cancelled(t *testing.T) {
+	t.Run("with cancel", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx  = context.Background()
+			_, c = errgroup.WithCancel(ctx)
+			eg   = errgroup.New(c)
+		)
+		err := eg.Go(func() error {
+			return nil
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+		require.False(t, eg.Cancelled())
+	})
+
+	t.Run("with error", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx  = context.Background()
+			_, c = errgroup.WithCancel(ctx)
+			eg   = errgroup.New(c)
+		)
+		err := eg.Go(func() error {
+			return errors.New("error")
+		})
+		require.NoError(t, err)
+
+		err = eg.Wait()
+		require.Error(t, err)
+		require.True(t, eg.Cancelled())
+	})
+
+	t.Run("with parent cancel", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx, cancel = context.WithCancel(context.Background())
+			_, c        = errgroup.WithCancel(ctx)
+			eg          = errgroup.New(c)
+		)
+		cancel()
+
+		err := eg.Wait()
+		require.NoError(t, err)
+		require.True(t, eg.Cancelled())
+	})
 }
 
-func TestGroup_TryGo(t *testing.T) {
-	t.Run("default", func(t *testing.T) {
+func TestGroup_Pause(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			eg       errgroup.Group
+			barrier  = make(chan struct{})
+			launched = make(chan struct{})
+		)
+		err := eg.Go(func() error {
+			_ = <-barrier
+			return nil
+		})
+		require.NoError(t, err)
+
+		eg.Pause()
+
+		err = eg.TryGo(func() error {
+			return nil
+		})
+		require.Error(t, err)
+
+		var pe *errgroup.PausedError
+		require.ErrorAs(t, err, &pe)
+
+		go func() {
+			_ = eg.Go(func() error {
+				return nil
+			})
+			close(launched)
+		}()
+
+		close(barrier)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+
+		select {
+		case <-launched:
+			t.Fatal("function launched while paused")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		eg.Resume()
+		<-launched
+
+		err = eg.Wait()
+		require.NoError(t, err)
+	})
+
+	t.Run("with cancel", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx  = context.Background()
+			_, c = errgroup.WithCancel(ctx)
+			eg   = errgroup.New(c)
+		)
+		eg.Pause()
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			eg.Cancel()
+		}()
+
+		err := eg.Go(func() error {
+			return nil
+		})
+		require.Error(t, err)
+
+		var ce *errgroup.CancelError
+		require.ErrorAs(t, err, &ce)
+	})
+
+	t.Run("with go variants", func(t *testing.T) {
 		t.Parallel()
 
-		const numGoroutines = 1 << 4
+		var (
+			ctx   = context.Background()
+			stop  = make(chan struct{})
+			goFns = map[string]func(eg *errgroup.Group, f func() error) error{
+				"GoWithStop": func(eg *errgroup.Group, f func() error) error {
+					return eg.GoWithStop(stop, f)
+				},
+				"GoWithContext": func(eg *errgroup.Group, f func() error) error {
+					return eg.GoWithContext(ctx, f)
+				},
+				"GoUrgent": func(eg *errgroup.Group, f func() error) error {
+					return eg.GoUrgent(f)
+				},
+			}
+		)
+		for name, goFn := range goFns {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
 
-		var eg errgroup.Group
-		for i := range numGoroutines {
-			err := eg.TryGo(func() error {
-				return fmt.Errorf("error %d", i)
+				var (
+					eg       errgroup.Group
+					ran      atomic.Bool
+					launched = make(chan error)
+				)
+				eg.Pause()
+
+				go func() {
+					launched <- goFn(&eg, func() error {
+						ran.Store(true)
+						return nil
+					})
+				}()
+
+				select {
+				case <-launched:
+					t.Fatal("function launched while paused")
+				case <-time.After(10 * time.Millisecond):
+				}
+				require.False(t, ran.Load())
+
+				eg.Resume()
+				require.NoError(t, <-launched)
+
+				err := eg.Wait()
+				require.NoError(t, err)
+				require.True(t, ran.Load())
 			})
-			require.NoError(t, err)
 		}
+	})
 
-		err := eg.Wait()
+	t.Run("with stop", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			eg   errgroup.Group
+			stop = make(chan struct{})
+		)
+		eg.Pause()
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			close(stop)
+		}()
+
+		err := eg.GoWithStop(stop, func() error {
+			return nil
+		})
+
+		var se *errgroup.StoppedError
+		require.ErrorAs(t, err, &se)
+	})
+
+	t.Run("with context", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			eg          errgroup.Group
+			ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+		)
+		defer cancel()
+		eg.Pause()
+
+		err := eg.GoWithContext(ctx, func() error {
+			return nil
+		})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestGroup_Seal(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			errTask = errors.New("task error")
+
+			ctx     = context.Background()
+			cctx, c = errgroup.WithCancel(ctx)
+			eg      = errgroup.New(c)
+			barrier = make(chan struct{})
+		)
+		err := eg.Go(func() error {
+			_ = <-barrier
+			return errTask
+		})
+		require.NoError(t, err)
+
+		eg.Seal()
+
+		err = eg.Go(func() error {
+			return nil
+		})
 		require.Error(t, err)
 
-		var e *multierr.Error
-		require.ErrorAs(t, err, &e)
-		require.Equal(t, numGoroutines, e.Len())
+		var se *errgroup.SealedError
+		require.ErrorAs(t, err, &se)
+
+		err = eg.TryGo(func() error {
+			return nil
+		})
+		require.ErrorAs(t, err, &se)
+		require.NoError(t, cctx.Err())
+
+		close(barrier)
+
+		err = eg.Wait()
+		require.ErrorIs(t, err, errTask)
 	})
+}
 
-	t.Run("with cancel", func(t *testing.T) {
+func TestGroup_Drain(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
 		t.Parallel()
 
 		const numGoroutines = 1 << 4
 
 		var (
-			ctx   = context.Background()
-			_, cc = errgroup.WithCancel(ctx)
-			eg    = errgroup.New(cc)
+			errTask = errors.New("task error")
 
-			barrier = make(chan struct{})
+			eg       errgroup.Group
+			barrier  = make(chan struct{})
+			finished atomic.Int32
 		)
-		for i := range numGoroutines {
+		for i := 0; i < numGoroutines; i++ {
 			err := eg.Go(func() error {
-				barrier <- struct{}{}
-				return fmt.Errorf("error %d", i)
+				_ = <-barrier
+				finished.Add(1)
+				if i == 0 {
+					return errTask
+				}
+
+				return nil
 			})
 			require.NoError(t, err)
 		}
 
-		for range numGoroutines {
+		drained := make(chan error)
+		go func() {
+			drained <- eg.Drain()
+		}()
+
+		require.Eventually(t, func() bool {
+			err := eg.TryGo(func() error {
+				return nil
+			})
+
+			var se *errgroup.SealedError
+			return errors.As(err, &se)
+		}, time.Second, time.Millisecond)
+
+		close(barrier)
+
+		err := <-drained
+		require.ErrorIs(t, err, errTask)
+		require.Equal(t, int32(numGoroutines), finished.Load())
+
+		var se *errgroup.SealedError
+		err = eg.Go(func() error {
+			return nil
+		})
+		require.ErrorAs(t, err, &se)
+
+		err = eg.TryGo(func() error {
+			return nil
+		})
+		require.ErrorAs(t, err, &se)
+	})
+}
+
+func TestGroup_Done(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			eg      errgroup.Group
+			barrier = make(chan struct{})
+		)
+		err := eg.Go(func() error {
 			_ = <-barrier
+			return nil
+		})
+		require.NoError(t, err)
+
+		done := eg.Done()
+		select {
+		case <-done:
+			require.Fail(t, "group finished early")
+		default:
 		}
 
-		err := eg.Wait()
-		require.Error(t, err)
+		close(barrier)
+		<-done
 
-		var e *multierr.Error
-		require.ErrorAs(t, err, &e)
-		require.Equal(t, 1, e.Len())
+		require.Equal(t, done, eg.Done())
 
-		err = eg.Go(func() error {
-			return errors.New("another error")
+		err = eg.Wait()
+		require.NoError(t, err)
+	})
+
+	t.Run("with later launch", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			eg      errgroup.Group
+			barrier = make(chan struct{})
+		)
+		idle := eg.Done()
+		select {
+		case <-idle:
+		default:
+			require.Fail(t, "idle group not finished")
+		}
+
+		err := eg.Go(func() error {
+			_ = <-barrier
+			return nil
 		})
-		require.Error(t, err)
+		require.NoError(t, err)
 
-		var ce *errgroup.CancelError
-		require.ErrorAs(t, err, &ce)
+		done := eg.Done()
+		require.NotEqual(t, idle, done)
+		select {
+		case <-done:
+			require.Fail(t, "group finished early")
+		default:
+		}
+
+		close(barrier)
+		<-done
+
+		err = eg.Wait()
+		require.NoError(t, err)
+	})
+}
+
+func TestGroup_Context(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			eg      errgroup.Group
+			ctx     = eg.Context()
+			barrier = make(chan struct{})
+		)
+		err := eg.Go(func() error {
+			_ = <-barrier
+			return nil
+		})
+		require.NoError(t, err)
+		require.NoError(t, ctx.Err())
+
+		close(barrier)
+
+		err = eg.Wait()
+		require.NoError(t, err)
+		require.ErrorIs(t, ctx.Err(), context.Canceled)
+		require.Equal(t, ctx, eg.Context())
+	})
+
+	t.Run("with cancel", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			errTask = errors.New("task error")
+
+			ctx     = context.Background()
+			cctx, c = errgroup.WithCancel(ctx)
+			eg      = errgroup.New(c)
+		)
+		require.Equal(t, cctx, eg.Context())
+
+		err := eg.Go(func() error {
+			return errTask
+		})
+		require.NoError(t, err)
+
+		<-eg.Context().Done()
+		require.ErrorIs(t, context.Cause(eg.Context()), errTask)
+
+		err = eg.Wait()
+		require.ErrorIs(t, err, errTask)
 	})
+}
 
+func TestGroup_MaxConcurrency(t *testing.T) {
 	t.Run("with limit", func(t *testing.T) {
 		t.Parallel()
 
@@ -180,36 +4235,109 @@ func TestGroup_TryGo(t *testing.T) {
 			eg = errgroup.New(
 				errgroup.WithLimit(maxGoroutines),
 			)
-			barrier = make(chan struct{}, maxGoroutines)
+			barrier = make(chan struct{})
 		)
-		for i := range maxGoroutines {
-			err := eg.TryGo(func() error {
+		for range maxGoroutines {
+			err := eg.Go(func() error {
 				_ = <-barrier
-				return fmt.Errorf("error %d", i)
+				return nil
 			})
 			require.NoError(t, err)
 		}
+		close(barrier)
 
-		for i := range numGoroutines - maxGoroutines {
-			err := eg.TryGo(func() error {
-				return fmt.Errorf("error %d", i)
+		for range numGoroutines - maxGoroutines {
+			err := eg.Go(func() error {
+				return nil
 			})
-			require.Error(t, err)
-
-			var le *errgroup.LimitError
-			require.ErrorAs(t, err, &le)
+			require.NoError(t, err)
 		}
 
+		err := eg.Wait()
+		require.NoError(t, err)
+		require.Equal(t, maxGoroutines, eg.MaxConcurrency())
+	})
+}
+
+func TestGroup_BlockedProducers(t *testing.T) {
+	t.Run("with limit", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			maxGoroutines = 1 << 2
+			numProducers  = 1 << 3
+		)
+
+		var (
+			eg = errgroup.New(
+				errgroup.WithLimit(maxGoroutines),
+			)
+			barrier = make(chan struct{})
+			f       = func() error {
+				_ = <-barrier
+				return nil
+			}
+		)
 		for range maxGoroutines {
-			barrier <- struct{}{}
+			err := eg.Go(f)
+			require.NoError(t, err)
+		}
+
+		var wg sync.WaitGroup
+		for range numProducers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_ = eg.Go(f)
+			}()
+		}
+
+		require.Eventually(t, func() bool {
+			return eg.BlockedProducers() == numProducers
+		}, time.Second, time.Millisecond)
+
+		close(barrier)
+		wg.Wait()
+
+		err := eg.Wait()
+		require.NoError(t, err)
+		require.Equal(t, 0, eg.BlockedProducers())
+	})
+}
+
+func TestGroup_MarshalJSON(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		eg := errgroup.New(
+			errgroup.WithLimit(numGoroutines),
+		)
+		for i := range numGoroutines {
+			err := eg.Go(func() error {
+				if i%2 == 0 {
+					return fmt.Errorf("error %d", i)
+				}
+
+				return nil
+			})
+			require.NoError(t, err)
 		}
 
 		err := eg.Wait()
 		require.Error(t, err)
 
-		var e *multierr.Error
-		require.ErrorAs(t, err, &e)
-		require.Equal(t, maxGoroutines, e.Len())
+		b, err := json.Marshal(eg)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"limit": 16,
+			"active": 0,
+			"completed": 16,
+			"succeeded": 8,
+			"failed": 8,
+			"cancelled": false
+		}`, string(b))
 	})
 }
 
@@ -244,3 +4372,87 @@ func BenchmarkGroup_TryGo(b *testing.B) {
 	}
 	_ = eg.Wait()
 }
+
+func BenchmarkWithSpinBeforeBlock(b *testing.B) {
+	const maxGoroutines = 1 << 2
+
+	benchmarks := []struct {
+		name       string
+		iterations int
+	}{
+		{name: "block", iterations: 0},
+		{name: "spin", iterations: 1 << 4},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ResetTimer()
+			b.ReportAllocs()
+
+			var (
+				eg = errgroup.New(
+					errgroup.WithLimit(maxGoroutines),
+					errgroup.WithSpinBeforeBlock(bm.iterations),
+				)
+				f = func() error {
+					return nil
+				}
+			)
+			for range b.N {
+				_ = eg.Go(f)
+			}
+			_ = eg.Wait()
+		})
+	}
+}
+
+func BenchmarkWithWorkerPool(b *testing.B) {
+	benchmarks := []struct {
+		name        string
+		configurers []errgroup.Configurer
+	}{
+		{name: "goroutine"},
+		{name: "pool", configurers: []errgroup.Configurer{
+			errgroup.WithWorkerPool(runtime.GOMAXPROCS(0)),
+		}},
+	}
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ResetTimer()
+			b.ReportAllocs()
+
+			var (
+				eg = errgroup.New(bm.configurers...)
+				f  = func() error {
+					return nil
+				}
+			)
+			for range b.N {
+				_ = eg.Go(f)
+			}
+			_ = eg.Wait()
+		})
+	}
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
+type syncBuffer struct {
+	mu sync.Mutex
+	sb strings.Builder
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sb.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sb.String()
+}
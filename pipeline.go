@@ -0,0 +1,151 @@
+package errgroup
+
+import (
+	"context"
+
+	"github.com/jordanhasgul/multierr"
+)
+
+// Pipeline processes items of type T through a chain of stages, producing
+// items of type R. Each stage runs on its own Group whose concurrency is
+// bounded by the limit supplied for that stage.
+type Pipeline[T, R any] struct {
+	start func(context.Context, context.CancelFunc, <-chan indexed[T]) (<-chan indexed[R], func() error)
+}
+
+type indexed[T any] struct {
+	index int
+	value T
+}
+
+// NewPipeline returns a new Pipeline consisting of a single stage that
+// applies f to each item, running at most limit invocations of f at once. A
+// limit of zero places no bound on the concurrency of the stage.
+func NewPipeline[T, R any](limit uint, f func(T) (R, error)) *Pipeline[T, R] {
+	start := func(ctx context.Context, cancel context.CancelFunc, in <-chan indexed[T]) (<-chan indexed[R], func() error) {
+		return runStage(ctx, cancel, in, limit, f)
+	}
+	return &Pipeline[T, R]{start: start}
+}
+
+// Then returns a new Pipeline that feeds the items produced by p into a
+// further stage that applies f to each item, running at most limit
+// invocations of f at once. A limit of zero places no bound on the
+// concurrency of the stage.
+func Then[T, M, R any](p *Pipeline[T, M], limit uint, f func(M) (R, error)) *Pipeline[T, R] {
+	start := func(ctx context.Context, cancel context.CancelFunc, in <-chan indexed[T]) (<-chan indexed[R], func() error) {
+		mid, waitPrev := p.start(ctx, cancel, in)
+		out, waitCurr := runStage(ctx, cancel, mid, limit, f)
+
+		wait := func() error {
+			var (
+				prevErr = waitPrev()
+				currErr = waitCurr()
+			)
+			if prevErr == nil && currErr == nil {
+				return nil
+			}
+
+			return multierr.Append(prevErr, currErr)
+		}
+		return out, wait
+	}
+	return &Pipeline[T, R]{start: start}
+}
+
+// Run processes inputs through every stage of the Pipeline and returns the
+// items produced by the final stage, in the same order as the inputs they
+// were derived from, alongside an error that aggregates any errors returned
+// by the stages.
+//
+// The first error returned by any stage cancels the whole Pipeline. Items
+// that fail, or that were still in flight when the Pipeline was cancelled,
+// do not appear in the returned items.
+func (p *Pipeline[T, R]) Run(inputs []T) ([]R, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	in := make(chan indexed[T])
+	go func() {
+		defer close(in)
+
+		for i, input := range inputs {
+			select {
+			case in <- indexed[T]{index: i, value: input}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var (
+		out, wait = p.start(ctx, cancel, in)
+
+		values  = make([]R, len(inputs))
+		present = make([]bool, len(inputs))
+	)
+	for item := range out {
+		values[item.index] = item.value
+		present[item.index] = true
+	}
+
+	outputs := make([]R, 0, len(inputs))
+	for i, value := range values {
+		if present[i] {
+			outputs = append(outputs, value)
+		}
+	}
+
+	return outputs, wait()
+}
+
+func runStage[T, R any](
+	ctx context.Context,
+	cancel context.CancelFunc,
+	in <-chan indexed[T],
+	limit uint,
+	f func(T) (R, error),
+) (<-chan indexed[R], func() error) {
+	var (
+		out  = make(chan indexed[R], limit)
+		done = make(chan struct{})
+		err  error
+	)
+	go func() {
+		defer close(done)
+		defer close(out)
+
+		var configurers []Configurer
+		if limit > 0 {
+			configurers = append(configurers, WithLimit(limit))
+		}
+
+		eg := New(configurers...)
+		for item := range in {
+			if ctx.Err() != nil {
+				continue
+			}
+
+			_ = eg.Go(func() error {
+				value, err := f(item.value)
+				if err != nil {
+					cancel()
+					return err
+				}
+
+				select {
+				case out <- indexed[R]{index: item.index, value: value}:
+				case <-ctx.Done():
+				}
+				return nil
+			})
+		}
+		err = eg.Wait()
+	}()
+
+	wait := func() error {
+		<-done
+		return err
+	}
+	return out, wait
+}
@@ -0,0 +1,106 @@
+package errgroup_test
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jordanhasgul/errgroup"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipeline_Run(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		const numItems = 1 << 8
+
+		var (
+			p = errgroup.Then(
+				errgroup.NewPipeline(4, func(i int) (int, error) {
+					return i * i, nil
+				}),
+				2,
+				func(i int) (string, error) {
+					return strconv.Itoa(i), nil
+				},
+			)
+
+			inputs = make([]int, numItems)
+		)
+		for i := range inputs {
+			inputs[i] = i
+		}
+
+		outputs, err := p.Run(inputs)
+		require.NoError(t, err)
+		require.Len(t, outputs, numItems)
+		for i, output := range outputs {
+			require.Equal(t, strconv.Itoa(i*i), output)
+		}
+	})
+
+	t.Run("with limit", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			maxGoroutines = 1 << 2
+			numItems      = 1 << 8
+		)
+
+		var (
+			active atomic.Int32
+			p      = errgroup.NewPipeline(maxGoroutines, func(i int) (int, error) {
+				n := active.Add(1)
+				defer active.Add(-1)
+				if n > maxGoroutines {
+					return 0, fmt.Errorf("too many goroutines - got: %d, want: %d", n, maxGoroutines)
+				}
+
+				return i, nil
+			})
+		)
+
+		outputs, err := p.Run(make([]int, numItems))
+		require.NoError(t, err)
+		require.Len(t, outputs, numItems)
+	})
+
+	t.Run("with error", func(t *testing.T) {
+		t.Parallel()
+
+		const numItems = 1 << 8
+
+		var (
+			errStage = errors.New("stage error")
+
+			processed atomic.Int32
+			p         = errgroup.Then(
+				errgroup.NewPipeline(1, func(i int) (int, error) {
+					if i == 0 {
+						return 0, errStage
+					}
+
+					return i, nil
+				}),
+				1,
+				func(i int) (int, error) {
+					processed.Add(1)
+					return i, nil
+				},
+			)
+
+			inputs = make([]int, numItems)
+		)
+		for i := range inputs {
+			inputs[i] = i
+		}
+
+		outputs, err := p.Run(inputs)
+		require.ErrorIs(t, err, errStage)
+		require.Less(t, len(outputs), numItems)
+		require.Less(t, processed.Load(), int32(numItems))
+	})
+}
@@ -0,0 +1,73 @@
+package errgroup
+
+import "sync"
+
+// pool is a fixed size set of worker goroutines that execute the tasks
+// submitted to it. The workers are started when the pool is created, and
+// can be stopped and then restarted by submitting another task.
+type pool struct {
+	workers int
+
+	lock    sync.RWMutex
+	tasks   chan task
+	running sync.WaitGroup
+}
+
+func newPool(workers int) *pool {
+	p := &pool{workers: max(workers, 1)}
+	p.start()
+	return p
+}
+
+// start starts the workers. The caller must hold lock, or otherwise have
+// exclusive access to the pool.
+func (p *pool) start() {
+	p.tasks = make(chan task)
+	for range p.workers {
+		p.running.Add(1)
+		go p.work(p.tasks)
+	}
+}
+
+func (p *pool) work(tasks <-chan task) {
+	defer p.running.Done()
+
+	for t := range tasks {
+		t.execute()
+	}
+}
+
+// submit blocks until one of the workers takes t, starting the workers if
+// they have been stopped.
+func (p *pool) submit(t task) {
+	for {
+		p.lock.RLock()
+		if p.tasks != nil {
+			p.tasks <- t
+			p.lock.RUnlock()
+			return
+		}
+		p.lock.RUnlock()
+
+		p.lock.Lock()
+		if p.tasks == nil {
+			p.start()
+		}
+		p.lock.Unlock()
+	}
+}
+
+// stop stops the workers, blocking until they have exited. Any tasks that
+// have already been taken by a worker are executed first.
+func (p *pool) stop() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.tasks == nil {
+		return
+	}
+
+	close(p.tasks)
+	p.tasks = nil
+	p.running.Wait()
+}
@@ -0,0 +1,128 @@
+package errgroup
+
+import (
+	"container/list"
+	"sync"
+)
+
+// semaphore is a weighted semaphore whose limit can be changed while it is
+// in use. Waiters are served in FIFO order.
+type semaphore struct {
+	lock    sync.Mutex
+	limit   int64
+	used    int64
+	waiters list.List
+}
+
+type waiter struct {
+	n     int64
+	ready chan struct{}
+}
+
+func newSemaphore(limit int64) *semaphore {
+	return &semaphore{limit: limit}
+}
+
+// tryAcquire acquires n units without blocking, reporting whether it was
+// able to.
+func (s *semaphore) tryAcquire(n int64) bool {
+	_, _, _, ok := s.tryAcquireSnapshot(n)
+	return ok
+}
+
+// tryAcquireSnapshot behaves like tryAcquire, except that it also returns
+// the units in use, the limit and the number of queued waiters, all read
+// under the same lock as the attempt, so that a failure can be explained.
+func (s *semaphore) tryAcquireSnapshot(n int64) (int64, int64, int, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	used, limit, queued := s.used, s.limit, s.waiters.Len()
+	if used+n <= limit && queued == 0 {
+		s.used += n
+		return used, limit, queued, true
+	}
+
+	return used, limit, queued, false
+}
+
+// acquire blocks until n units are acquired, returning true, or until stop
+// is closed, returning false.
+func (s *semaphore) acquire(n int64, stop <-chan struct{}) bool {
+	s.lock.Lock()
+	if s.used+n <= s.limit && s.waiters.Len() == 0 {
+		s.used += n
+		s.lock.Unlock()
+		return true
+	}
+
+	var (
+		ready = make(chan struct{})
+		elem  = s.waiters.PushBack(waiter{n: n, ready: ready})
+	)
+	s.lock.Unlock()
+
+	select {
+	case <-ready:
+		return true
+	case <-stop:
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	select {
+	case <-ready:
+		// The units were acquired after stop was closed, so give them back.
+		s.used -= n
+	default:
+		s.waiters.Remove(elem)
+	}
+
+	s.notify()
+	return false
+}
+
+// release releases n units.
+func (s *semaphore) release(n int64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.used -= n
+	s.notify()
+}
+
+// resize changes the limit. Units that are already held are unaffected, so
+// the number held may exceed the limit until enough are released.
+func (s *semaphore) resize(limit int64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.limit = limit
+	s.notify()
+}
+
+// size returns the number of units held and the limit.
+func (s *semaphore) size() (int64, int64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.used, s.limit
+}
+
+func (s *semaphore) notify() {
+	for {
+		front := s.waiters.Front()
+		if front == nil {
+			return
+		}
+
+		w := front.Value.(waiter)
+		if s.used+w.n > s.limit {
+			return
+		}
+
+		s.used += w.n
+		s.waiters.Remove(front)
+		close(w.ready)
+	}
+}
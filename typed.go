@@ -0,0 +1,238 @@
+package errgroup
+
+import "sync"
+
+// TypedGroup manages the execution of goroutines that run functions of type
+// func() (T, error), collecting the values returned by those that succeed.
+type TypedGroup[T any] struct {
+	group Group
+
+	resultsLock sync.Mutex
+	outcomes    []Result[T]
+	stream      chan Result[T]
+	first       *Result[T]
+	firstCh     chan struct{}
+}
+
+// Result carries the outcome of a function passed to a TypedGroup, which is
+// either the value it returned or, if Err is non-nil, the error.
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
+// NewTyped returns a new TypedGroup that has been configured by applying
+// any supplied configurers.
+func NewTyped[T any](configurers ...Configurer) *TypedGroup[T] {
+	group := &TypedGroup[T]{}
+	group.group.init(configurers...)
+	return group
+}
+
+// Go launches f in another goroutine, in the same manner as Group.Go. The
+// outcome of f is kept until it is either sent on the channel returned by
+// TypedGroup.Stream or, if f returned a nil error, its value is returned by
+// TypedGroup.Wait. The outcome is that of f once any retries,
+// transformation and recovery configured for the TypedGroup have been
+// applied, so each function passed to Go has exactly one outcome, even if
+// it was called several times or not at all.
+func (g *TypedGroup[T]) Go(f func() (T, error)) error {
+	var result T
+	run := func() error {
+		value, err := f()
+		result = value
+		return err
+	}
+	finish := func(err error) {
+		outcome := Result[T]{Value: result, Err: err}
+
+		g.resultsLock.Lock()
+		stream := g.stream
+		if stream == nil {
+			g.outcomes = append(g.outcomes, outcome)
+		}
+
+		if g.first == nil {
+			g.first = &outcome
+			close(g.firstDone())
+		}
+		g.resultsLock.Unlock()
+
+		// The stream cannot be closed while the outcome is being sent, as
+		// it is only closed once no functions are executing.
+		if stream != nil {
+			stream <- outcome
+		}
+	}
+	return g.group.goTask(task{f: run, finish: finish})
+}
+
+// Stream returns a channel on which the outcome of each function passed to
+// the TypedGroup is sent exactly once. Outcomes of functions that finished
+// before Stream was called are sent first, followed by the outcomes of the
+// rest as soon as each finishes executing. No ordering is provided beyond
+// that: outcomes are sent in the order the functions finished, not the order
+// they were launched. The channel is unbuffered, so a function that finishes
+// while the channel is open does not release its slot until its Result has
+// been received.
+//
+// The channel is closed once no functions passed to the TypedGroup are
+// executing, so Stream is typically called after every function has been
+// passed to TypedGroup.Go, and the channel ranged over until it is closed.
+// The outcomes of functions launched after the channel has been closed are
+// kept for a later call to Stream or TypedGroup.Wait. Calling Stream while
+// the channel is open returns the same channel.
+//
+// Values sent on the stream are not collected, so are not returned by
+// TypedGroup.Wait.
+func (g *TypedGroup[T]) Stream() <-chan Result[T] {
+	g.resultsLock.Lock()
+	defer g.resultsLock.Unlock()
+
+	if g.stream == nil {
+		var (
+			stream = make(chan Result[T])
+			replay = g.outcomes
+		)
+		g.stream = stream
+		g.outcomes = nil
+		go g.feed(stream, replay)
+	}
+
+	return g.stream
+}
+
+// feed sends replay on stream, and then closes stream once no functions
+// passed to the TypedGroup are executing.
+func (g *TypedGroup[T]) feed(stream chan Result[T], replay []Result[T]) {
+	for _, outcome := range replay {
+		stream <- outcome
+	}
+
+	for {
+		<-g.group.Done()
+
+		g.resultsLock.Lock()
+		// A function launched since the Group went idle may be about to
+		// send on stream, so keep waiting for it.
+		if g.group.active.Load() == 0 {
+			close(stream)
+			g.stream = nil
+			g.resultsLock.Unlock()
+			return
+		}
+		g.resultsLock.Unlock()
+	}
+}
+
+// WaitAny blocks until the first function passed to the TypedGroup has
+// finished executing and returns its outcome, as delivered by
+// TypedGroup.Go once any retries, transformation and recovery have been
+// applied, leaving the remaining functions executing. The bool reports
+// whether any function had been launched; if not, WaitAny returns
+// immediately. Calling WaitAny multiple times returns the outcome of the
+// same function.
+func (g *TypedGroup[T]) WaitAny() (T, error, bool) {
+	if g.group.launched.Load() == 0 {
+		var zero T
+		return zero, nil, false
+	}
+
+	g.resultsLock.Lock()
+	done := g.firstDone()
+	g.resultsLock.Unlock()
+
+	<-done
+
+	g.resultsLock.Lock()
+	defer g.resultsLock.Unlock()
+	return g.first.Value, g.first.Err, true
+}
+
+// firstDone returns a channel that is closed once the first function has
+// finished executing. The caller must hold resultsLock.
+func (g *TypedGroup[T]) firstDone() chan struct{} {
+	if g.firstCh == nil {
+		g.firstCh = make(chan struct{})
+	}
+
+	return g.firstCh
+}
+
+// Wait blocks until all goroutines managed by the TypedGroup have finished
+// executing and returns the values collected from the functions that
+// succeeded, in no particular order, alongside an error that aggregates any
+// errors that occurred within each goroutine. The values of functions whose
+// outcomes were sent on the channel returned by TypedGroup.Stream are not
+// returned.
+func (g *TypedGroup[T]) Wait() ([]T, error) {
+	err := g.group.Wait()
+
+	g.resultsLock.Lock()
+	defer g.resultsLock.Unlock()
+
+	var results []T
+	for _, outcome := range g.outcomes {
+		if outcome.Err == nil {
+			results = append(results, outcome.Value)
+		}
+	}
+
+	return results, err
+}
+
+// KeyedGroup manages the execution of goroutines that run functions of type
+// func() (T, error), collecting the values returned by those that succeed
+// under a key supplied by the caller.
+type KeyedGroup[K comparable, T any] struct {
+	group Group
+
+	resultsLock sync.Mutex
+	results     map[K]T
+}
+
+// NewKeyed returns a new KeyedGroup that has been configured by applying
+// any supplied configurers.
+func NewKeyed[K comparable, T any](configurers ...Configurer) *KeyedGroup[K, T] {
+	group := &KeyedGroup[K, T]{}
+	group.group.init(configurers...)
+	return group
+}
+
+// Go launches f in another goroutine, in the same manner as Group.Go. If f
+// returns a nil error, the value it returns is collected under key,
+// replacing any value previously collected under the same key.
+func (g *KeyedGroup[K, T]) Go(key K, f func() (T, error)) error {
+	return g.group.Go(func() error {
+		result, err := f()
+		if err != nil {
+			return err
+		}
+
+		g.resultsLock.Lock()
+		defer g.resultsLock.Unlock()
+
+		if g.results == nil {
+			g.results = make(map[K]T)
+		}
+		g.results[key] = result
+		return nil
+	})
+}
+
+// WaitMap blocks until all goroutines managed by the KeyedGroup have
+// finished executing and returns the values collected from the functions
+// that succeeded, keyed by the key each was launched with, alongside an
+// error that aggregates any errors that occurred within each goroutine.
+// The keys of functions that failed are absent from the map.
+func (g *KeyedGroup[K, T]) WaitMap() (map[K]T, error) {
+	err := g.group.Wait()
+
+	g.resultsLock.Lock()
+	defer g.resultsLock.Unlock()
+
+	if g.results == nil {
+		g.results = make(map[K]T)
+	}
+	return g.results, err
+}
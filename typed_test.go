@@ -0,0 +1,424 @@
+package errgroup_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jordanhasgul/errgroup"
+	"github.com/jordanhasgul/multierr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypedGroup_Go(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var tg errgroup.TypedGroup[int]
+		for i := range numGoroutines {
+			err := tg.Go(func() (int, error) {
+				if i%2 == 0 {
+					return 0, fmt.Errorf("error %d", i)
+				}
+
+				return i, nil
+			})
+			require.NoError(t, err)
+		}
+
+		results, err := tg.Wait()
+		require.Error(t, err)
+
+		var e *multierr.Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, numGoroutines/2, e.Len())
+
+		slices.Sort(results)
+		require.Len(t, results, numGoroutines/2)
+		for i, result := range results {
+			require.Equal(t, 2*i+1, result)
+		}
+	})
+
+	t.Run("with cancel", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			ctx      = context.Background()
+			cctx, cc = errgroup.WithCancel(ctx)
+			tg       = errgroup.NewTyped[int](cc)
+		)
+		err := tg.Go(func() (int, error) {
+			return 0, fmt.Errorf("error")
+		})
+		require.NoError(t, err)
+
+		<-cctx.Done()
+
+		err = tg.Go(func() (int, error) {
+			return 1, nil
+		})
+		var ce *errgroup.CancelError
+		require.ErrorAs(t, err, &ce)
+
+		results, err := tg.Wait()
+		require.Error(t, err)
+		require.Empty(t, results)
+	})
+
+	t.Run("with error transform", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			errDropped = errors.New("dropped error")
+
+			tg = errgroup.NewTyped[int](
+				errgroup.WithErrorTransform(func(err error) error {
+					return nil
+				}),
+			)
+		)
+		err := tg.Go(func() (int, error) {
+			return 1, errDropped
+		})
+		require.NoError(t, err)
+
+		_, err, ok := tg.WaitAny()
+		require.ErrorIs(t, err, errDropped)
+		require.True(t, ok)
+
+		results, err := tg.Wait()
+		require.NoError(t, err)
+		require.Empty(t, results)
+	})
+}
+
+func TestTypedGroup_Stream(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		tg := errgroup.NewTyped[int](
+			errgroup.WithLimit(1 << 2),
+		)
+		for i := range numGoroutines {
+			err := tg.Go(func() (int, error) {
+				if i%2 == 0 {
+					return 0, fmt.Errorf("error %d", i)
+				}
+
+				return i, nil
+			})
+			require.NoError(t, err)
+		}
+
+		var (
+			values []int
+			failed int
+		)
+		for result := range tg.Stream() {
+			if result.Err != nil {
+				failed++
+				continue
+			}
+
+			values = append(values, result.Value)
+		}
+		require.Equal(t, numGoroutines/2, failed)
+
+		slices.Sort(values)
+		require.Len(t, values, numGoroutines/2)
+		for i, value := range values {
+			require.Equal(t, 2*i+1, value)
+		}
+
+		results, err := tg.Wait()
+		require.Error(t, err)
+		require.Empty(t, results)
+	})
+
+	t.Run("with finished functions", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var (
+			tg       errgroup.TypedGroup[int]
+			returned atomic.Int32
+		)
+		for i := range numGoroutines {
+			err := tg.Go(func() (int, error) {
+				returned.Add(1)
+				return i, nil
+			})
+			require.NoError(t, err)
+		}
+
+		require.Eventually(t, func() bool {
+			return returned.Load() == numGoroutines
+		}, time.Second, time.Millisecond)
+		time.Sleep(10 * time.Millisecond)
+
+		var values []int
+		for result := range tg.Stream() {
+			require.NoError(t, result.Err)
+			values = append(values, result.Value)
+		}
+
+		slices.Sort(values)
+		require.Len(t, values, numGoroutines)
+		for i, value := range values {
+			require.Equal(t, i, value)
+		}
+	})
+
+	t.Run("with retry", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var (
+			errTask = errors.New("task error")
+
+			tg = errgroup.NewTyped[int](
+				errgroup.WithRetry(2),
+			)
+			calls atomic.Int32
+		)
+		for range numGoroutines {
+			err := tg.Go(func() (int, error) {
+				calls.Add(1)
+				return 0, errTask
+			})
+			require.NoError(t, err)
+		}
+
+		var results int
+		for result := range tg.Stream() {
+			require.ErrorIs(t, result.Err, errTask)
+			results++
+		}
+		require.Equal(t, numGoroutines, results)
+		require.Equal(t, int32(3*numGoroutines), calls.Load())
+
+		_, err := tg.Wait()
+		require.ErrorIs(t, err, errTask)
+	})
+
+	t.Run("with fault injection", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var (
+			errFault = errors.New("fault error")
+
+			tg = errgroup.NewTyped[int](
+				errgroup.WithFaultInjection(1, errFault),
+			)
+		)
+		for i := range numGoroutines {
+			err := tg.Go(func() (int, error) {
+				return i, nil
+			})
+			require.NoError(t, err)
+		}
+
+		var results int
+		for result := range tg.Stream() {
+			require.ErrorIs(t, result.Err, errFault)
+			results++
+		}
+		require.Equal(t, numGoroutines, results)
+
+		_, err := tg.Wait()
+		require.ErrorIs(t, err, errFault)
+	})
+
+	t.Run("with recover", func(t *testing.T) {
+		t.Parallel()
+
+		tg := errgroup.NewTyped[int](
+			errgroup.WithRecover(),
+		)
+		err := tg.Go(func() (int, error) {
+			panic("task panic")
+		})
+		require.NoError(t, err)
+
+		stream := tg.Stream()
+		result, ok := <-stream
+		require.True(t, ok)
+
+		var pe *errgroup.PanicError
+		require.ErrorAs(t, result.Err, &pe)
+
+		_, ok = <-stream
+		require.False(t, ok)
+
+		_, err = tg.Wait()
+		require.ErrorAs(t, err, &pe)
+	})
+}
+
+func TestTypedGroup_WaitAny(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var (
+			tg      errgroup.TypedGroup[int]
+			barrier = make(chan struct{})
+		)
+		_, _, ok := tg.WaitAny()
+		require.False(t, ok)
+
+		for i := range numGoroutines {
+			err := tg.Go(func() (int, error) {
+				if i > 0 {
+					_ = <-barrier
+				}
+
+				return i, nil
+			})
+			require.NoError(t, err)
+		}
+
+		value, err, ok := tg.WaitAny()
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, 0, value)
+
+		close(barrier)
+
+		results, err := tg.Wait()
+		require.NoError(t, err)
+		require.Len(t, results, numGoroutines)
+	})
+
+	t.Run("with error", func(t *testing.T) {
+		t.Parallel()
+
+		var tg errgroup.TypedGroup[int]
+		err := tg.Go(func() (int, error) {
+			return 0, fmt.Errorf("error")
+		})
+		require.NoError(t, err)
+
+		_, err, ok := tg.WaitAny()
+		require.EqualError(t, err, "error")
+		require.True(t, ok)
+
+		_, err = tg.Wait()
+		require.Error(t, err)
+	})
+
+	t.Run("with fault injection", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			errFault = errors.New("fault error")
+
+			tg = errgroup.NewTyped[int](
+				errgroup.WithFaultInjection(1, errFault),
+			)
+		)
+		err := tg.Go(func() (int, error) {
+			return 1, nil
+		})
+		require.NoError(t, err)
+
+		_, err, ok := tg.WaitAny()
+		require.ErrorIs(t, err, errFault)
+		require.True(t, ok)
+
+		_, err = tg.Wait()
+		require.ErrorIs(t, err, errFault)
+	})
+
+	t.Run("with retry", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			errTask = errors.New("task error")
+
+			tg = errgroup.NewTyped[int](
+				errgroup.WithRetry(1),
+			)
+			calls atomic.Int32
+		)
+		err := tg.Go(func() (int, error) {
+			if calls.Add(1) == 1 {
+				return 0, errTask
+			}
+
+			return 1, nil
+		})
+		require.NoError(t, err)
+
+		value, err, ok := tg.WaitAny()
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, 1, value)
+
+		values, err := tg.Wait()
+		require.NoError(t, err)
+		require.Equal(t, []int{1}, values)
+	})
+}
+
+func TestKeyedGroup_WaitMap(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		const numGoroutines = 1 << 4
+
+		var kg errgroup.KeyedGroup[string, int]
+		for i := range numGoroutines {
+			err := kg.Go(fmt.Sprintf("key %d", i), func() (int, error) {
+				if i%2 == 0 {
+					return 0, fmt.Errorf("error %d", i)
+				}
+
+				return i * i, nil
+			})
+			require.NoError(t, err)
+		}
+
+		results, err := kg.WaitMap()
+		require.Error(t, err)
+
+		var e *multierr.Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, numGoroutines/2, e.Len())
+
+		require.Len(t, results, numGoroutines/2)
+		for i := range numGoroutines {
+			result, ok := results[fmt.Sprintf("key %d", i)]
+			require.Equal(t, i%2 == 1, ok)
+			if ok {
+				require.Equal(t, i*i, result)
+			}
+		}
+	})
+
+	t.Run("without tasks", func(t *testing.T) {
+		t.Parallel()
+
+		kg := errgroup.NewKeyed[int, string](
+			errgroup.WithLimit(1 << 2),
+		)
+		results, err := kg.WaitMap()
+		require.NoError(t, err)
+		require.NotNil(t, results)
+		require.Empty(t, results)
+	})
+}